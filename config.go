@@ -1,21 +1,35 @@
 package config
 
 import (
+	"bytes"
 	"errors"
+	"flag"
 	"fmt"
+	"go/token"
 	"io"
+	"io/fs"
 	"io/ioutil"
+	"net/http"
+	"net/url"
 	"os"
+	"os/exec"
 	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pcelvng/go-config/load"
 	"github.com/pcelvng/go-config/load/env"
 	flg "github.com/pcelvng/go-config/load/flag"
 	"github.com/pcelvng/go-config/load/json"
+	"github.com/pcelvng/go-config/load/systemd"
 	"github.com/pcelvng/go-config/load/toml"
 	"github.com/pcelvng/go-config/load/yaml"
 	"github.com/pcelvng/go-config/render"
+	"github.com/pcelvng/go-config/schema"
 	"github.com/pcelvng/go-config/util"
 	"github.com/pcelvng/go-config/util/node"
 )
@@ -25,11 +39,66 @@ func Load(appCfgs ...interface{}) error {
 	return defaultCfg.Load(appCfgs...)
 }
 
+// LoadReader is a package wrapper around *GoConfig.LoadReader().
+func LoadReader(ext string, r io.Reader, appCfgs ...interface{}) error {
+	return defaultCfg.LoadReader(ext, r, appCfgs...)
+}
+
+// LoadWithPrefixes is a package wrapper around *GoConfig.LoadWithPrefixes().
+func LoadWithPrefixes(prefixCfgs map[string]interface{}) error {
+	return defaultCfg.LoadWithPrefixes(prefixCfgs)
+}
+
+// HelpText is a package wrapper around *GoConfig.HelpText().
+func HelpText(appCfgs ...interface{}) (string, error) {
+	return defaultCfg.HelpText(appCfgs...)
+}
+
+// LoadFS is a package wrapper around *GoConfig.LoadFS().
+func LoadFS(fsys fs.FS, pth string, appCfgs ...interface{}) error {
+	return defaultCfg.LoadFS(fsys, pth, appCfgs...)
+}
+
+// LoadMap is a package wrapper around *GoConfig.LoadMap().
+func LoadMap(ext, pth string) (map[string]interface{}, error) {
+	return defaultCfg.LoadMap(ext, pth)
+}
+
 // LoadOrDie is a package wrapper around *GoConfig.LoadOrDie().
 func LoadOrDie(appCfgs ...interface{}) {
 	defaultCfg.LoadOrDie(appCfgs...)
 }
 
+// LoadInto loads into the app config "c" with the default config (see Load)
+// and returns "c".
+//
+//	appCfg, err := config.LoadInto(&AppConfig{Host: "localhost"})
+func LoadInto[T any](c *T) (*T, error) {
+	if err := defaultCfg.Load(c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// MustLoadInto behaves like LoadInto except errors are handled the
+// same as LoadOrDie.
+//
+//	appCfg := config.MustLoadInto(&AppConfig{Host: "localhost"})
+func MustLoadInto[T any](c *T) *T {
+	defaultCfg.LoadOrDie(c)
+	return c
+}
+
+// Freeze returns a deep copy (see util.DeepCopy) of the loaded app config "c". The copy
+// can be handed to other goroutines without racing on changes made to "c".
+//
+// Note: Freeze is a snapshot, not a lock. The copy itself can still be modified and
+// changes to "c" after calling Freeze are not reflected in the copy.
+func Freeze[T any](c *T) *T {
+	return util.DeepCopy(c).(*T)
+}
+
 // With is a package wrapper around *GoConfig.With().
 func With(with ...string) *GoConfig {
 	return defaultCfg.With(with...)
@@ -45,11 +114,156 @@ func DisableStdFlags() *GoConfig {
 	return defaultCfg.DisableStdFlags()
 }
 
+// DisableConfigFlag is a package wrapper around *GoConfig.DisableConfigFlag().
+func DisableConfigFlag() *GoConfig {
+	return defaultCfg.DisableConfigFlag()
+}
+
+// DisableGenFlag is a package wrapper around *GoConfig.DisableGenFlag().
+func DisableGenFlag() *GoConfig {
+	return defaultCfg.DisableGenFlag()
+}
+
+// DisableGenResolvedFlag is a package wrapper around *GoConfig.DisableGenResolvedFlag().
+func DisableGenResolvedFlag() *GoConfig {
+	return defaultCfg.DisableGenResolvedFlag()
+}
+
+// DisableShowFlag is a package wrapper around *GoConfig.DisableShowFlag().
+func DisableShowFlag() *GoConfig {
+	return defaultCfg.DisableShowFlag()
+}
+
+// DisableVersionFlag is a package wrapper around *GoConfig.DisableVersionFlag().
+func DisableVersionFlag() *GoConfig {
+	return defaultCfg.DisableVersionFlag()
+}
+
+// DisableCheckFlag is a package wrapper around *GoConfig.DisableCheckFlag().
+func DisableCheckFlag() *GoConfig {
+	return defaultCfg.DisableCheckFlag()
+}
+
+// DisableConfigURLFlag is a package wrapper around *GoConfig.DisableConfigURLFlag().
+func DisableConfigURLFlag() *GoConfig {
+	return defaultCfg.DisableConfigURLFlag()
+}
+
 // SetConfigPath is a package wrapper around *GoConfig.SetConfigPath().
 func SetConfigPath(pth string) *GoConfig {
 	return defaultCfg.SetConfigPath(pth)
 }
 
+// ConfigPathEnv is a package wrapper around *GoConfig.ConfigPathEnv().
+func ConfigPathEnv(name string) *GoConfig {
+	return defaultCfg.ConfigPathEnv(name)
+}
+
+// WithDefaults is a package wrapper around *GoConfig.WithDefaults().
+func WithDefaults(defaults interface{}) *GoConfig {
+	return defaultCfg.WithDefaults(defaults)
+}
+
+// JSONSchema is a package wrapper around *GoConfig.JSONSchema().
+func JSONSchema() ([]byte, error) {
+	return defaultCfg.JSONSchema()
+}
+
+// RequireConfigFile is a package wrapper around *GoConfig.RequireConfigFile().
+func RequireConfigFile() *GoConfig {
+	return defaultCfg.RequireConfigFile()
+}
+
+// ConfigFileLoaded is a package wrapper around *GoConfig.ConfigFileLoaded().
+func ConfigFileLoaded() bool {
+	return defaultCfg.ConfigFileLoaded()
+}
+
+// Check is a package wrapper around *GoConfig.Check().
+func Check(appCfgs ...interface{}) error {
+	return defaultCfg.Check(appCfgs...)
+}
+
+// LoadOnly is a package wrapper around *GoConfig.LoadOnly().
+func LoadOnly(names ...string) error {
+	return defaultCfg.LoadOnly(names...)
+}
+
+// Get is a package wrapper around *GoConfig.Get().
+func Get(fullName string) (interface{}, error) {
+	return defaultCfg.Get(fullName)
+}
+
+// Set is a package wrapper around *GoConfig.Set().
+func Set(fullName, value string) error {
+	return defaultCfg.Set(fullName, value)
+}
+
+// Drift is a package wrapper around *GoConfig.Drift().
+func Drift(pth string) ([]DriftEntry, error) {
+	return defaultCfg.Drift(pth)
+}
+
+// Sources is a package wrapper around *GoConfig.Sources().
+func Sources() map[string]string {
+	return defaultCfg.Sources()
+}
+
+// WithSecretResolver is a package wrapper around *GoConfig.WithSecretResolver().
+func WithSecretResolver(tagName string, r SecretResolver) *GoConfig {
+	return defaultCfg.WithSecretResolver(tagName, r)
+}
+
+// EnableExecSources is a package wrapper around *GoConfig.EnableExecSources().
+func EnableExecSources() *GoConfig {
+	return defaultCfg.EnableExecSources()
+}
+
+// WithLoader is a package wrapper around *GoConfig.WithLoader().
+func WithLoader(name string, enabled func() bool) *GoConfig {
+	return defaultCfg.WithLoader(name, enabled)
+}
+
+// WithFileRootKey is a package wrapper around *GoConfig.WithFileRootKey().
+func WithFileRootKey(key string) *GoConfig {
+	return defaultCfg.WithFileRootKey(key)
+}
+
+// WithLenientFileParsing is a package wrapper around *GoConfig.WithLenientFileParsing().
+func WithLenientFileParsing() *GoConfig {
+	return defaultCfg.WithLenientFileParsing()
+}
+
+// WithInlineConfigEnv is a package wrapper around *GoConfig.WithInlineConfigEnv().
+func WithInlineConfigEnv(name, format string) *GoConfig {
+	return defaultCfg.WithInlineConfigEnv(name, format)
+}
+
+// WithProfile is a package wrapper around *GoConfig.WithProfile().
+func WithProfile(envVarOrValue string) *GoConfig {
+	return defaultCfg.WithProfile(envVarOrValue)
+}
+
+// WarnOutput is a package wrapper around *GoConfig.WarnOutput().
+func WarnOutput(w io.Writer) *GoConfig {
+	return defaultCfg.WarnOutput(w)
+}
+
+// Override is a package wrapper around *GoConfig.Override().
+func Override(s string) *GoConfig {
+	return defaultCfg.Override(s)
+}
+
+// WithTemplateMerge is a package wrapper around *GoConfig.WithTemplateMerge().
+func WithTemplateMerge() *GoConfig {
+	return defaultCfg.WithTemplateMerge()
+}
+
+// NoInitNil is a package wrapper around *GoConfig.NoInitNil().
+func NoInitNil() *GoConfig {
+	return defaultCfg.NoInitNil()
+}
+
 // Version is a package wrapper around GoConfig.Version().
 func Version(s string) *GoConfig {
 	return defaultCfg.Version(s)
@@ -63,6 +277,30 @@ func WithFlagOptions(o flg.Options) *GoConfig {
 	return defaultCfg.WithFlagOptions(o)
 }
 
+func WithNonExiting() *GoConfig {
+	return defaultCfg.WithNonExiting()
+}
+
+func WithSelfReferences() *GoConfig {
+	return defaultCfg.WithSelfReferences()
+}
+
+func WithCollisionStrategy(strategy string) *GoConfig {
+	return defaultCfg.WithCollisionStrategy(strategy)
+}
+
+func WithSliceSplitter(fn func(raw, sep string, isString bool) []string) *GoConfig {
+	return defaultCfg.WithSliceSplitter(fn)
+}
+
+func WithIndexedEnvSlices() *GoConfig {
+	return defaultCfg.WithIndexedEnvSlices()
+}
+
+func WithWarnUnusedEnv(w io.Writer) *GoConfig {
+	return defaultCfg.WithWarnUnusedEnv(w)
+}
+
 func FieldHelp(fieldName, helpTxt string) *GoConfig {
 	return defaultCfg.FieldHelp(fieldName, helpTxt)
 }
@@ -71,6 +309,26 @@ func FieldTag(fieldName, tagName, helpTxt string) *GoConfig {
 	return defaultCfg.FieldTag(fieldName, tagName, helpTxt)
 }
 
+func TypeTag(valueType, tagName, tagValue string) *GoConfig {
+	return defaultCfg.TypeTag(valueType, tagName, tagValue)
+}
+
+func IgnoreField(fieldName string) *GoConfig {
+	return defaultCfg.IgnoreField(fieldName)
+}
+
+func IgnoreFieldIf(fieldName string, cond func() bool) *GoConfig {
+	return defaultCfg.IgnoreFieldIf(fieldName, cond)
+}
+
+func FieldTransform(fieldName string, fn func(string) (string, error)) *GoConfig {
+	return defaultCfg.FieldTransform(fieldName, fn)
+}
+
+func FieldValidate(fieldName string, fn func(value interface{}) error) *GoConfig {
+	return defaultCfg.FieldValidate(fieldName, fn)
+}
+
 // New creates a new config.
 func New() *GoConfig {
 	return NewWithPrefix("")
@@ -112,6 +370,12 @@ func NewWithPrefix(prefix string) *GoConfig {
 				FileExts: []string{},
 				Loader:   flg.NewLoader(flg.Options{}).WithPrefix(prefix),
 			},
+			// Note: "systemd" is not in the default load order. Include it with "With".
+			"systemd": {
+				Name:     "systemd",
+				FileExts: []string{},
+				Loader:   systemd.NewLoader(),
+			},
 		},
 		with: []string{
 			// Listed in the default load order.
@@ -163,6 +427,11 @@ type GoConfig struct {
 	// lus contains a map by Name of registered LoadUnloaders.
 	lus map[string]*LoadUnloader
 
+	// loadLus contains the LoadUnloaders of the current load. The "env" and "flag"
+	// entries are copies with the loader options applied (see prepLoaders) so
+	// registered loaders are not modified.
+	loadLus map[string]*LoadUnloader
+
 	// flgLoader holds the flag loader for pre-loading to handle the help screen and
 	// standard options.
 	flgLoader *flg.Loader
@@ -173,20 +442,126 @@ type GoConfig struct {
 	stdFlgs    *stdFlgs
 	flgOptions flg.Options
 
+	// loadFlgOptions are the flag options of the current load (see prepLoaders).
+	loadFlgOptions flg.Options
+
 	showOptions render.Options
 
 	// tagOverrides stores struct field tag overrides allowing for long tag values and setting values at runtime.
 	tagOverrides []tagOverride
 
+	// typeTagOverrides stores struct field tag values set on all fields of a value type.
+	typeTagOverrides []typeTagOverride
+
 	// showRenderer contains an instance of the showRenderer for customizing the display of
 	// loaded values.
 	showRenderer *render.Renderer
 
+	// cfgPathEnv is the name of the env variable the config path is read from (if set).
+	cfgPathEnv string
+
+	// inlineCfgEnv is the name of the env variable the whole config is read from (if set)
+	// with the file loader of the inlineCfgFormat file extension.
+	inlineCfgEnv    string
+	inlineCfgFormat string
+
 	// version contains the application name and version as provided by calling "Version".
 	version string
 
 	// stdFlgsDisabled will disable std flag support such as usage of the --gen flag.
 	stdFlgsDisabled bool
+
+	// stdFlgsOff contains the stdFlgs field names of individually disabled standard flags.
+	stdFlgsOff map[string]bool
+
+	// noInitNil will set nil struct pointers back to nil after loading when no values were loaded into them.
+	noInitNil bool
+
+	// defaults is an optional struct (or struct pointer) of default values applied before loading.
+	defaults interface{}
+
+	// nGrps contains the app config node groups of the most recent load.
+	nGrps []*node.Nodes
+
+	// requireCfgFile will return an error on load when no config file path is resolved.
+	requireCfgFile bool
+
+	// cfgFileLoaded is true when the most recent load read in a config file.
+	cfgFileLoaded bool
+
+	// secretResolvers are run in order after all loaders.
+	secretResolvers []secretResolver
+
+	// execSources enables running the 'exec' tag commands after all loaders (see EnableExecSources).
+	execSources bool
+
+	// loaderEnabled contains optional predicates by loader name. A loader is
+	// skipped when its predicate returns false.
+	loaderEnabled map[string]func() bool
+
+	// templateMerge will merge a generated config template into the existing
+	// config file (when supported by the unloader).
+	templateMerge bool
+
+	// profile is the env variable name or value of the config file section to load.
+	profile string
+
+	// fileRootKey is the (dot "." separated) key of the config file section to load.
+	fileRootKey string
+
+	// warnOut is where warnings (such as deprecated field warnings) are written. Defaults to os.Stderr.
+	warnOut io.Writer
+
+	// overrides are comma separated "FullName=value" lists applied in order after all loaders.
+	overrides []string
+
+	// fieldTransforms are applied in order after all loaders and secret resolvers.
+	fieldTransforms []fieldTransform
+
+	// fieldValidators are run in order with post load validation.
+	fieldValidators []fieldValidator
+
+	// lenientFiles strips a leading BOM and normalizes line endings of config files
+	// before they are parsed (see WithLenientFileParsing).
+	lenientFiles bool
+
+	// fragments are the config structs loaded by LoadWithPrefixes. Fragments implementing
+	// Validator are validated with the app configs.
+	fragments []interface{}
+
+	// nonExiting returns standard flag action errors (such as ErrHelpRequested) from Load
+	// instead of exiting.
+	nonExiting bool
+
+	// selfRefs enables expanding "${FieldName}" references to other field values after all loaders.
+	selfRefs bool
+
+	// sliceSplitter optionally replaces how env and flag slice values are split.
+	sliceSplitter func(raw, sep string, isString bool) []string
+
+	// collisionStrategy optionally sets how env and flag names generated by more than one field are resolved.
+	collisionStrategy string
+
+	// indexedEnv enables reading env slices from numbered env variables (see WithIndexedEnvSlices).
+	indexedEnv bool
+
+	// unusedEnvOut is where unused prefixed env variables are reported (see WithWarnUnusedEnv).
+	unusedEnvOut io.Writer
+}
+
+type secretResolver struct {
+	tagName string
+	r       SecretResolver
+}
+
+type fieldTransform struct {
+	fieldName string
+	fn        func(string) (string, error)
+}
+
+type fieldValidator struct {
+	fieldName string
+	fn        func(value interface{}) error
 }
 
 type tagOverride struct {
@@ -194,28 +569,63 @@ type tagOverride struct {
 	Tag       string
 	TagValue  string
 
+	// cond is an optional condition checked at load time. The tag
+	// is not set when it returns false.
+	cond func() bool
+
 	err   error
 	found bool
 }
 
+type typeTagOverride struct {
+	ValueType string
+	Tag       string
+	TagValue  string
+}
+
 var (
-	cfgPathHelp   = "Config file path. Extension must be %s."
-	genConfigHelp = "Generate config template (%s)."
+	cfgPathHelp     = "Config file path. Extension must be %s."
+	cfgURLHelp      = "Config http(s) URL. Loaded before the --config file."
+	cfgTypeHelp     = "Config URL type (%s). Defaults to the URL extension."
+	genConfigHelp   = "Generate config template (%s)."
+	genResolvedHelp = "Generate config file with the loaded values (%s). Hidden values are redacted."
+
+	reqTag = "req" // Marks a field as required. "required" is a synonym (see node.Node.ReqTag).
+
+	enumTag = "enum" // Lists the allowed field values.
+
+	execTag = "exec" // Command whose output is the field value (see EnableExecSources).
+
+	validateRule      = "Validate"      // Validation failure rule of Validator failures.
+	fieldValidateRule = "FieldValidate" // Validation failure rule of FieldValidate failures.
+
+	deprecatedTag = "deprecated" // Deprecation message of a field. A warning is written when the field is loaded.
+
+	sourceMeta    = "source"  // Node meta key of the name of the loader that set the field value.
+	defaultSource = "default" // Source of fields not set by any loader.
+	setSource     = "set"     // Source of fields set with Set.
 
 	// TODO: built in support for validate struct tag.
 	//validateTag = "validate" // See https://godoc.org/gopkg.in/go-playground/validator.v9
 
 	defaultCfg = New()
+
+	// httpClient fetches --config-url configs.
+	httpClient = &http.Client{Timeout: 30 * time.Second}
 )
 
 // stdFlgs contains the set of standard flags used by the config library.
 type stdFlgs struct {
-	ConfigPath string `flag:"config,c" env:"-" toml:"-"` // Dynamically generated "help" text.
+	ConfigPath string `flag:"config,c" env:"-" toml:"-"`    // Dynamically generated "help" text.
+	ConfigURL  string `flag:"config-url" env:"-" toml:"-"`  // Dynamically generated "help" text.
+	ConfigType string `flag:"config-type" env:"-" toml:"-"` // Dynamically generated "help" text.
 
 	// TODO: value can be path or extension. 'env' can also be 'sh'. 'env' or 'sh' is also attempts to make executable.
-	Gen         string `flag:"gen,g" env:"-" toml:"-"` // Dynamically generated "help" text.
+	Gen         string `flag:"gen,g" env:"-" toml:"-"`        // Dynamically generated "help" text.
+	GenResolved string `flag:"gen-resolved" env:"-" toml:"-"` // Dynamically generated "help" text.
 	ShowValues  bool   `flag:"show" env:"-" toml:"-" help:"Print loaded config values and exit."`
 	ShowVersion bool   `flag:"version,v" env:"-" toml:"-" help:"Show application version and exit."`
+	CheckConfig bool   `flag:"check" env:"-" toml:"-" help:"Validate loaded config values, print the result and exit."`
 }
 
 // Load handles:
@@ -224,8 +634,222 @@ type stdFlgs struct {
 // - final config load
 // - post load validation by:
 //   - enforcing "validate" struct field tag directives TODO
-//   - calling the custom Validate method (if implemented) TODO
+//   - enforcing the 'req:"true"' struct field tag
+//   - calling the custom Validate method (if implemented)
+//
+// When the --check standard flag is provided the validation result is printed
+// and the application exits with code 0 (valid) or 1 (invalid).
 func (g *GoConfig) Load(appCfgs ...interface{}) error {
+	return g.load(func(cfgPath string, stdNGrp, nGrps []*node.Nodes) error {
+		return g.loadAll(cfgPath, stdNGrp, nGrps)
+	}, appCfgs...)
+}
+
+// LoadReader behaves like Load except the config file bytes are read from "r" instead
+// of from a config file path. Useful for tests and for configs fetched by the caller.
+//
+// "ext" selects the file loader (i.e. "toml", "yaml", "json") and must be a registered
+// file extension. Only the loader matching "ext" is run along with the non-file loaders
+// (such as "env" and "flag") in the "With" list. The --config flag and SetConfigPath value
+// are not used.
+func (g *GoConfig) LoadReader(ext string, r io.Reader, appCfgs ...interface{}) error {
+	ext = strings.Trim(strings.TrimSpace(ext), ".")
+	if !g.hasRegisteredExt(ext) {
+		return &LoaderNotFoundErr{lExt: ext}
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	return g.load(func(_ string, stdNGrp, nGrps []*node.Nodes) error {
+		g.cfgFileLoaded = false
+		if err := g.loadBytes([]cfgSource{{ext: ext, b: b}}, stdNGrp, nGrps); err != nil {
+			return err
+		}
+
+		g.cfgFileLoaded = true
+		return nil
+	}, appCfgs...)
+}
+
+// LoadFS behaves like Load except the config file is read from "pth" in the
+// filesystem "fsys" (such as an embed.FS) instead of from the --config flag or
+// SetConfigPath value. The file loader is selected by the "pth" file extension.
+//
+// Values from the non-file loaders (such as "env" and "flag") in the "With" list
+// are still loaded in order so later loaders (such as "flag") override the embedded values.
+func (g *GoConfig) LoadFS(fsys fs.FS, pth string, appCfgs ...interface{}) error {
+	ext := strings.TrimPrefix(path.Ext(pth), ".")
+	if ext == "" {
+		return &ConfigExtNotFoundErr{path: pth}
+	}
+	if !g.hasRegisteredExt(ext) {
+		return &LoaderNotFoundErr{lExt: ext}
+	}
+
+	b, err := fs.ReadFile(fsys, pth)
+	if err != nil {
+		return &ConfigFileErr{path: pth, err: err}
+	}
+
+	return g.load(func(_ string, stdNGrp, nGrps []*node.Nodes) error {
+		g.cfgFileLoaded = false
+		if err := g.loadBytes([]cfgSource{{ext: ext, b: b}}, stdNGrp, nGrps); err != nil {
+			return err
+		}
+
+		g.cfgFileLoaded = true
+		return nil
+	}, appCfgs...)
+}
+
+// LoadWithPrefixes behaves like Load except each config struct pointer in "prefixCfgs" is
+// loaded under its own prefix (the map key) within the single load. Useful to compose a
+// config from several library provided structs that each expect their own prefix.
+//
+//	config.NewWithPrefix("app").LoadWithPrefixes(map[string]interface{}{
+//	    "redis": &redisCfg, // APP_REDIS_HOST, --redis-host, [redis] host = ...
+//	    "db":    &dbCfg,    // APP_DB_HOST, --db-host, [db] host = ...
+//	})
+//
+// The prefix is the parent struct name of the config struct fields (as if the config struct
+// were a field named after the prefix) so it applies to all loaders and the global prefix is
+// still prepended. Field names (such as for FieldHelp or Get) include the prefix. For example,
+// "Redis.Host". Config structs implementing Validator are validated.
+//
+// An error is returned if a value is not a struct pointer or a prefix is not a valid name.
+func (g *GoConfig) LoadWithPrefixes(prefixCfgs map[string]interface{}) error {
+	prefixes := make([]string, 0, len(prefixCfgs))
+	for prefix := range prefixCfgs {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	fields := make([]reflect.StructField, 0, len(prefixes))
+	names := make(map[string]string) // field name -> prefix
+	for _, prefix := range prefixes {
+		v := reflect.ValueOf(prefixCfgs[prefix])
+		if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+			return fmt.Errorf("prefix '%v': config must be a non-nil struct pointer", prefix)
+		}
+
+		name := util.ToCamel(prefix)
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return fmt.Errorf("prefix '%v' is not a valid name", prefix)
+		}
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("prefixes '%v' and '%v' generate the same name '%v'", other, prefix, name)
+		}
+		names[name] = prefix
+
+		fields = append(fields, reflect.StructField{Name: name, Type: v.Type()})
+	}
+
+	// Each config struct is a (pointer) field of a single app config.
+	appCfg := reflect.New(reflect.StructOf(fields))
+	g.fragments = make([]interface{}, 0, len(prefixes))
+	for i, prefix := range prefixes {
+		appCfg.Elem().Field(i).Set(reflect.ValueOf(prefixCfgs[prefix]))
+		g.fragments = append(g.fragments, prefixCfgs[prefix])
+	}
+	defer func() { g.fragments = nil }()
+
+	return g.Load(appCfg.Interface())
+}
+
+// LoadMap reads the config file at "pth" into a generic map using the native unmarshal
+// of the file loader registered for "ext" (for example "yaml"). The node tree is not used
+// so no struct tags, defaults, env, flags or validation are applied.
+//
+// "ext" is optional and if empty then the "pth" file extension is used. If "pth" is empty
+// then the ConfigPathEnv env variable value or the SetConfigPath value is used.
+//
+// The file loader must implement the load.MapLoader interface (toml, yaml and json do).
+func (g *GoConfig) LoadMap(ext, pth string) (map[string]interface{}, error) {
+	if pth == "" && g.cfgPathEnv != "" {
+		pth = os.Getenv(g.cfgPathEnv)
+	}
+	if pth == "" {
+		pth = g.stdFlgs.ConfigPath
+	}
+	if pth == "" {
+		return nil, &ConfigFileErr{err: errors.New("no config file path provided")}
+	}
+
+	ext = strings.Trim(strings.TrimSpace(ext), ".")
+	if ext == "" {
+		ext = strings.TrimPrefix(path.Ext(pth), ".")
+	}
+	if ext == "" {
+		return nil, &ConfigExtNotFoundErr{path: pth}
+	}
+
+	var lu *LoadUnloader
+	for _, regLU := range g.lus {
+		if itemIn(ext, regLU.FileExts) != "" {
+			lu = regLU
+		}
+	}
+	if lu == nil {
+		return nil, &LoaderNotFoundErr{lExt: ext}
+	}
+
+	ml, ok := lu.Loader.(load.MapLoader)
+	if !ok {
+		return nil, fmt.Errorf("loader '%v' does not support loading into a map", lu.Name)
+	}
+
+	b, err := ioutil.ReadFile(pth)
+	if err != nil {
+		return nil, &ConfigFileErr{path: pth, err: err}
+	}
+
+	m, err := ml.LoadMap(g.fileBytes(b))
+	if err != nil {
+		return nil, &ParseErr{lName: lu.Name, err: err}
+	}
+
+	return m, nil
+}
+
+// LoadOnly runs only the named loaders (in the provided order) against the app config(s)
+// of the most recent load. The rest of the load pipeline (standard flags, template generation,
+// show, secret resolvers, exec sources, overrides and validation) is skipped so fields set by
+// other loaders are left as-is. Useful to periodically refresh values from a remote loader.
+//
+// File loaders (such as "toml") are not supported since no config file is read. The "flag"
+// loader is not supported since the standard flags are not loaded.
+func (g *GoConfig) LoadOnly(names ...string) error {
+	if len(g.nGrps) == 0 {
+		return fmt.Errorf("nothing loaded to refresh")
+	}
+
+	for _, name := range names {
+		lu, ok := g.loadLus[name]
+		if !ok {
+			return &LoaderNotFoundErr{lName: name}
+		}
+		if len(lu.FileExts) > 0 {
+			return fmt.Errorf("loader '%v' is a file loader", name)
+		}
+		if name == "flag" {
+			return fmt.Errorf("loader '%v' cannot be refreshed", name)
+		}
+
+		if err := g.loadWith(name, lu.Loader, nil, g.nGrps, g.nGrps); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// load runs the full load pipeline. "loadFn" is called to read in all the values
+// once the standard flags have been handled. "cfgPath" is the resolved config file
+// path (see ConfigPathEnv) and "stdNGrp" is empty when standard flags are disabled.
+func (g *GoConfig) load(loadFn func(cfgPath string, stdNGrp, nGrps []*node.Nodes) error, appCfgs ...interface{}) error {
 	if !g.initialized {
 		panic("uninitialized go config")
 	}
@@ -246,7 +870,8 @@ func (g *GoConfig) Load(appCfgs ...interface{}) error {
 	}
 	cfgs = append(cfgs, appCfgs...)
 	allNGrps := node.MakeAllNodes(node.Options{
-		NoFollow: []string{"time.Time"},
+		NoFollow:  []string{"time.Time"},
+		NoInitNil: g.noInitNil,
 	}, cfgs...)
 
 	stdNGrp := make([]*node.Nodes, 0)
@@ -255,6 +880,7 @@ func (g *GoConfig) Load(appCfgs ...interface{}) error {
 		stdNGrp = allNGrps[0:1]
 		nGrps = allNGrps[1:]
 	}
+	g.nGrps = nGrps
 
 	// Apply field tag overrides.
 	err = g.applyTagOverrides(nGrps)
@@ -262,6 +888,30 @@ func (g *GoConfig) Load(appCfgs ...interface{}) error {
 		return err
 	}
 
+	// Validate field transform field names.
+	err = g.checkFieldTransforms(nGrps)
+	if err != nil {
+		return err
+	}
+
+	// Validate field validator field names.
+	err = g.checkFieldValidators(nGrps)
+	if err != nil {
+		return err
+	}
+
+	// Apply defaults (if provided).
+	//
+	// Note: defaults are applied before initializing the showRenderer so
+	// they are recorded as the default values.
+	err = g.applyDefaults(nGrps)
+	if err != nil {
+		return err
+	}
+
+	// Record deprecated field values to detect loaded values.
+	depVals := deprecatedVals(nGrps)
+
 	// Initialize showRenderer.
 	//
 	// Default values are recorded with the showRenderer on initialization.
@@ -271,82 +921,1104 @@ func (g *GoConfig) Load(appCfgs ...interface{}) error {
 		return err
 	}
 
+	// The SetConfigPath value is restored after the load (see configPath).
+	defCfgPath := g.stdFlgs.ConfigPath
+
 	// Handle flag pre-loading.
 	//
 	// Note: flags are loaded twice - once to handle
 	// the help screen and handle standard options and again later on for the final
 	// load resolution. This is the initial load.
-	g.prepStdFlags(nGrps[0])
-	preLdr := flg.NewLoader(g.flgOptions)
-	// Handle flags, std flags enabled combinations. If both flags and std flags
-	// are disabled then do not create a flag set at all.
-	switch true {
-	case itemIn("flag", g.with) == "" && !g.stdFlgsDisabled:
-		// flags disabled
-		// std flags enabled
-		if err := preLdr.Load([]byte{}, stdNGrp); err != nil {
-			return err
-		}
-	case itemIn("flag", g.with) == "flag" && g.stdFlgsDisabled:
-		// flags enabled
-		// std flags disabled
-		if err := preLdr.Load([]byte{}, nGrps); err != nil {
-			return err
-		}
-	case itemIn("flag", g.with) == "flag" && !g.stdFlgsDisabled:
-		// flags enabled
-		// std flags enabled
-		if err := preLdr.Load([]byte{}, allNGrps); err != nil {
-			return err
+	if !g.stdFlgsDisabled {
+		g.prepStdFlags(stdNGrp[0])
+	}
+	g.prepLoaders()
+	preLdr := flg.NewLoader(g.loadFlgOptions)
+
+	// Record env names for the help menu.
+	if g.loadFlgOptions.ShowEnvInHelp && itemIn("env", g.with) == "env" {
+		if el, ok := g.loadLus["env"].Loader.(*env.EnvLoader); ok {
+			if err := el.RecordNames(nGrps); err != nil {
+				return err
+			}
 		}
 	}
 
+	// Append mode slices are restored after pre-loading so flag
+	// values are not appended twice.
+	restoreSlices := snapshotAppendSlices(nGrps)
+	recordSources := trackSources(nGrps)
+
+	// If both flags and std flags are disabled then do not create a flag set at all.
+	if flgNGrps := g.flagNGrps(stdNGrp, nGrps); len(flgNGrps) > 0 {
+		err = preLdr.Load([]byte{}, flgNGrps)
+	}
+	if g.nonExiting && errors.Is(err, flag.ErrHelp) {
+		return ErrHelpRequested
+	}
+	if err != nil {
+		return err
+	}
+	recordSources("flag")
+	restoreSlices()
+
+	// Resolve the config path of this load. The SetConfigPath value is restored
+	// after loading so the flag value is not the default of the next load.
+	cfgPath := g.configPath(defCfgPath, preLdr.IsSet("config"))
+	defer func() { g.stdFlgs.ConfigPath = defCfgPath }()
+
 	if !g.stdFlgsDisabled {
 		// Handle showing app version.
 		if g.stdFlgs.ShowVersion {
-			g.showVersion()
+			return g.showVersion()
 		}
 
 		// Generate config template (if option provided).
-		err = g.writeTemplate(g.stdFlgs.Gen, nGrps[1:])
+		err = g.writeTemplate(g.stdFlgs.Gen, cfgPath, nGrps)
 		if err != nil {
 			return err
 		}
 	}
 
 	// Read in all values.
-	// Note: If stdFlgs are disabled then g.stdFlags.ConfigPath will be empty
-	// unless the user has set a default value via *GoConfig.SetConfigPath().
-	err = g.loadAll(g.stdFlgs.ConfigPath, nGrps)
+	err = loadFn(cfgPath, stdNGrp, nGrps)
+	if err != nil {
+		if g.stdFlgs.CheckConfig {
+			return g.checkConfig(err)
+		}
+		return err
+	}
+
+	// Apply overrides.
+	err = g.applyOverrides(nGrps)
+	if err != nil {
+		if g.stdFlgs.CheckConfig {
+			return g.checkConfig(err)
+		}
+		return err
+	}
+
+	// Expand references to other fields (if enabled).
+	if g.selfRefs {
+		err = resolveSelfRefs(nGrps)
+		if err != nil {
+			if g.stdFlgs.CheckConfig {
+				return g.checkConfig(err)
+			}
+			return err
+		}
+	}
+
+	// Warn about loaded deprecated fields.
+	g.warnDeprecated(depVals)
+
+	// Resolve secret references.
+	err = g.resolveSecrets(nGrps)
+	if err != nil {
+		if g.stdFlgs.CheckConfig {
+			return g.checkConfig(err)
+		}
+		return err
+	}
+
+	// Run exec source commands (if enabled).
+	if g.execSources {
+		err = resolveExecs(nGrps)
+		if err != nil {
+			if g.stdFlgs.CheckConfig {
+				return g.checkConfig(err)
+			}
+			return err
+		}
+	}
+
+	// Apply field transforms.
+	err = g.applyFieldTransforms(nGrps)
 	if err != nil {
+		if g.stdFlgs.CheckConfig {
+			return g.checkConfig(err)
+		}
 		return err
 	}
 
+	// Set unused struct pointers back to nil (if enabled).
+	for _, nGrp := range nGrps {
+		nGrp.PruneNil()
+	}
+
+	// Generate config file with the loaded values (if option provided).
+	//
+	// Values are redacted in a copy so the app config values are not changed.
+	if g.stdFlgs.GenResolved != "" {
+		resolved := make([]*node.Nodes, 0, len(nGrps))
+		for _, nGrp := range nGrps {
+			resolved = append(resolved, nGrp.Copy(node.Options{NoFollow: []string{"time.Time"}}))
+		}
+		redactValues(resolved)
+		err = g.writeTemplate(g.stdFlgs.GenResolved, cfgPath, resolved)
+		if err != nil {
+			return err
+		}
+	}
+
 	// ShowValues
 	if g.stdFlgs.ShowValues {
 		err = g.ShowValues()
 		if err != nil {
 			return err
 		}
-		os.Exit(0)
+		return g.stdFlagDone(ErrValuesShown)
 	}
 
-	// Validate if struct implements validator interface.
+	// Validate required fields and if struct implements validator interface.
 	// TODO: implement full validate tag support.
-	// TODO: validate on the 'req:"true"' struct tag.
+	err = validate(nGrps, append(append([]interface{}{}, appCfgs...), g.fragments...), g.fieldValidators)
+
+	// CheckConfig
+	if g.stdFlgs.CheckConfig {
+		return g.checkConfig(err)
+	}
+
+	return err
+}
+
+// deprecatedVal is the recorded field value of a deprecated node.
+type deprecatedVal struct {
+	n   *node.Node
+	val interface{}
+}
+
+// deprecatedVals returns a copy of the current field value of each node with
+// the 'deprecated' struct tag.
+func deprecatedVals(nGrps []*node.Nodes) []deprecatedVal {
+	vals := make([]deprecatedVal, 0)
+	for _, nGrp := range nGrps {
+		for _, n := range nGrp.List() {
+			if n.GetTag(deprecatedTag) != "" {
+				vals = append(vals, deprecatedVal{n: n, val: util.DeepCopy(n.FieldValue.Interface())})
+			}
+		}
+	}
+
+	return vals
+}
+
+// warnDeprecated writes a warning with the 'deprecated' tag message for each
+// deprecated field with a value that changed from the recorded value.
+func (g *GoConfig) warnDeprecated(vals []deprecatedVal) {
+	w := g.warnOut
+	if w == nil {
+		w = os.Stderr
+	}
+
+	for _, dv := range vals {
+		if reflect.DeepEqual(dv.val, dv.n.FieldValue.Interface()) {
+			continue
+		}
+		fmt.Fprintf(w, "warning: field '%v' is deprecated: %v\n", dv.n.FullName(), dv.n.GetTag(deprecatedTag))
+	}
+}
+
+// trackSources records a copy of the current value of each value field (struct
+// fields other than time.Time are skipped) and returns a func that sets the
+// source meta of the fields with a changed value to the loader "name".
+func trackSources(nGrps []*node.Nodes) (record func(name string)) {
+	vals := make(map[*node.Node]interface{})
+	for _, nGrp := range nGrps {
+		for _, n := range nGrp.List() {
+			if n.IsStruct() && !n.IsTime() {
+				continue
+			}
+			vals[n] = util.DeepCopy(n.FieldValue.Interface())
+		}
+	}
+
+	return func(name string) {
+		for n, val := range vals {
+			if !reflect.DeepEqual(val, n.FieldValue.Interface()) {
+				n.SetMeta(sourceMeta, name)
+			}
+		}
+	}
+}
+
+// snapshotAppendSlices records the current value of each append mode slice field
+// (see node.IsSliceAppend) and returns a func that restores the recorded values.
+func snapshotAppendSlices(nGrps []*node.Nodes) (restore func()) {
+	vals := make(map[*node.Node]reflect.Value)
+	for _, nGrp := range nGrps {
+		for _, n := range nGrp.List() {
+			if n.IsSliceAppend() {
+				vals[n] = reflect.ValueOf(n.FieldValue.Interface())
+			}
+		}
+	}
+
+	return func() {
+		for n, v := range vals {
+			n.FieldValue.Set(v)
+		}
+	}
+}
+
+// redactValues sets the value of fields hidden with the 'show:"false"' or 'secret:"true"' tag
+// (on the field or a parent struct) to "[redacted]" (string fields) or the zero value and masks
+// string fields with the 'mask' tag. Non-string secrets are always set to the zero value. Used to
+// write the loaded values without secrets.
+func redactValues(nGrps []*node.Nodes) {
+	for _, nGrp := range nGrps {
+		for _, n := range nGrp.List() {
+			if n.IsStruct() && !n.IsTime() {
+				continue
+			}
+
+			hidden := n.IsSecret() && n.Kind() != reflect.String
+			for _, hn := range append(node.Parents(n, nGrp.Map()), n) {
+				hidden = hidden || !hn.IsShown()
+			}
+
+			switch {
+			case hidden && n.Kind() == reflect.String:
+				n.FieldValue.SetString(node.Redacted)
+			case hidden:
+				n.FieldValue.Set(reflect.Zero(n.FieldValue.Type()))
+			case n.GetTag("mask") != "" && n.Kind() == reflect.String:
+				n.FieldValue.SetString(n.DisplayString())
+			}
+		}
+	}
+}
+
+// flagNGrps returns the node groups with flags for the flags and std flags enabled
+// combinations. Empty when both flags and std flags are disabled.
+func (g *GoConfig) flagNGrps(stdNGrp, nGrps []*node.Nodes) []*node.Nodes {
+	switch true {
+	case itemIn("flag", g.with) == "" && !g.stdFlgsDisabled:
+		// flags disabled
+		// std flags enabled
+		return stdNGrp
+	case itemIn("flag", g.with) == "flag" && g.stdFlgsDisabled:
+		// flags enabled
+		// std flags disabled
+		return nGrps
+	case itemIn("flag", g.with) == "flag" && !g.stdFlgsDisabled:
+		// flags enabled
+		// std flags enabled
+		return append(append([]*node.Nodes{}, stdNGrp...), nGrps...)
+	}
+
+	return nil
+}
+
+// prepLoaders applies the loader options (such as the flag options, warning output,
+// slice splitter and name collision strategy) to copies of the "env" and "flag" loaders
+// when they are the go-config loaders. The copies are used for the current load so the
+// registered loaders and flag options are left as-is.
+func (g *GoConfig) prepLoaders() {
+	opts := g.flgOptions
+	if g.sliceSplitter != nil {
+		opts.SliceSplitter = g.sliceSplitter
+	}
+	if g.collisionStrategy != "" {
+		opts.CollisionStrategy = g.collisionStrategy
+	}
+	if g.nonExiting {
+		opts.ContinueOnError = true
+	}
+	g.loadFlgOptions = opts
+
+	g.loadLus = make(map[string]*LoadUnloader, len(g.lus))
+	for name, lu := range g.lus {
+		g.loadLus[name] = lu
+	}
+
+	if lu, ok := g.lus["env"]; ok {
+		c := *lu
+		if el, ok := lu.Loader.(*env.EnvLoader); ok {
+			l := *el
+			if g.warnOut != nil {
+				l.WithWarnOutput(g.warnOut)
+			}
+			if g.sliceSplitter != nil {
+				l.WithSliceSplitter(g.sliceSplitter)
+			}
+			if g.collisionStrategy != "" {
+				l.WithCollisionStrategy(g.collisionStrategy)
+			}
+			if g.indexedEnv {
+				l.WithIndexedSlices()
+			}
+			if g.unusedEnvOut != nil {
+				l.WithWarnUnused(g.unusedEnvOut).WithUsedNames(g.cfgPathEnv, g.inlineCfgEnv)
+			}
+			c.Loader = &l
+		}
+		if eu, ok := lu.Unloader.(*env.EnvUnloader); ok && g.collisionStrategy != "" {
+			u := *eu
+			u.WithCollisionStrategy(g.collisionStrategy)
+			c.Unloader = &u
+		}
+		g.loadLus["env"] = &c
+	}
+
+	if lu, ok := g.lus["flag"]; ok {
+		if _, ok := lu.Loader.(*flg.Loader); ok {
+			c := *lu
+			c.Loader = flg.NewLoader(opts).WithPrefix(g.prefix)
+			g.loadLus["flag"] = &c
+		}
+	}
+}
+
+// snapshotIgnored records a copy of the current value of each ignored field (see
+// isAnyIgnored) and returns a func that restores the recorded values. Ignored struct
+// fields restore the whole struct.
+func snapshotIgnored(nGrps []*node.Nodes) (restore func()) {
+	vals := make(map[*node.Node]reflect.Value)
+	for _, nGrp := range nGrps {
+		for _, n := range nGrp.List() {
+			if isAnyIgnored([]*node.Node{n}) {
+				vals[n] = reflect.ValueOf(util.DeepCopy(n.FieldValue.Interface()))
+			}
+		}
+	}
+
+	return func() {
+		for n, v := range vals {
+			n.FieldValue.Set(v)
+		}
+	}
+}
+
+// Check validates the config struct definitions of "appCfgs" without reading any values.
+// The checks include field tag override and field transform names, 'omitprefix' on
+// non-struct fields, 'enum' values not valid for the field type, 'mask' values that are
+// not a non-negative number, 'req' conditions referencing unknown fields, 'rest' field
+// types and the struct support of each loader in the "With" list (such as duplicate flag
+// names or unsupported field kinds).
+//
+// All struct problems found are returned as a *StructErr. Useful to unit test config
+// struct definitions.
+func (g *GoConfig) Check(appCfgs ...interface{}) error {
+	if len(appCfgs) == 0 {
+		return fmt.Errorf("nothing to check")
+	}
+
+	if err := util.AreStructPointers(appCfgs...); err != nil {
+		return err
+	}
+
+	cfgs := make([]interface{}, 0)
+	if !g.stdFlgsDisabled {
+		cfgs = append(cfgs, g.stdFlgs)
+	}
+	cfgs = append(cfgs, appCfgs...)
+	allNGrps := node.MakeAllNodes(node.Options{
+		NoFollow:  []string{"time.Time"},
+		NoInitNil: g.noInitNil,
+	}, cfgs...)
+
+	nGrps := allNGrps
+	if !g.stdFlgsDisabled {
+		g.prepStdFlags(allNGrps[0])
+		nGrps = allNGrps[1:]
+	}
+
+	failures := make([]ValidationFailure, 0)
+	if err := g.applyTagOverrides(nGrps); err != nil {
+		failures = append(failures, ValidationFailure{Rule: "tag", Message: err.Error()})
+	}
+	if err := g.checkFieldTransforms(nGrps); err != nil {
+		failures = append(failures, ValidationFailure{Rule: "transform", Message: err.Error()})
+	}
+	if err := g.checkFieldValidators(nGrps); err != nil {
+		failures = append(failures, ValidationFailure{Rule: fieldValidateRule, Message: err.Error()})
+	}
+	for _, nGrp := range nGrps {
+		failures = append(failures, structFailures(nGrp)...)
+	}
+
+	// Loader struct support is only checked when no other problems are found
+	// since loaders return the first problem (which may be one already found).
+	if len(failures) == 0 {
+		g.prepLoaders()
+		for _, name := range g.with {
+			if err := g.checkLoader(name, allNGrps, nGrps); err != nil {
+				failures = append(failures, ValidationFailure{Rule: name, Message: fmt.Sprintf("%v: %v", name, err)})
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return &StructErr{failures: failures}
+	}
+
+	return nil
+}
+
+// checkLoader returns the first struct support problem of the loader "name". A loader
+// panic (such as a file encoder reading an unsupported field kind) is returned as an error.
+func (g *GoConfig) checkLoader(name string, allNGrps, nGrps []*node.Nodes) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	if name == "flag" {
+		return flg.NewLoader(g.loadFlgOptions).Check(allNGrps)
+	}
+	if lu, ok := g.loadLus[name]; ok && lu.canUnload() {
+		_, err = lu.Unloader.Unload(nGrps)
+	}
+
+	return err
+}
+
+// structFailures returns the struct definition problems of the fields of "nGrp".
+func structFailures(nGrp *node.Nodes) []ValidationFailure {
+	failures := make([]ValidationFailure, 0)
+	for _, n := range nGrp.List() {
+		if !n.IsStruct() || n.IsTime() {
+			for _, tag := range []string{"env", "flag"} {
+				if strings.Split(n.GetTag(tag), ",")[0] == "omitprefix" {
+					failures = append(failures, ValidationFailure{
+						FieldName: n.FullName(),
+						Rule:      tag,
+						Message:   fmt.Sprintf("field '%v': 'omitprefix' cannot be used on non-struct field types", n.FullName()),
+					})
+				}
+			}
+		}
+
+		for _, v := range n.EnumValues() {
+			if err := n.CheckValue(v); err != nil {
+				failures = append(failures, ValidationFailure{
+					FieldName: n.FullName(),
+					Rule:      enumTag,
+					Message:   fmt.Sprintf("field '%v' 'enum' value '%v' is not valid for type '%v'", n.FullName(), v, n.ValueType()),
+				})
+			}
+		}
+
+		if maskV := n.GetTag("mask"); maskV != "" {
+			if visible, err := strconv.Atoi(maskV); err != nil || visible < 0 {
+				failures = append(failures, ValidationFailure{
+					FieldName: n.FullName(),
+					Rule:      "mask",
+					Message:   fmt.Sprintf("field '%v' 'mask' value '%v' must be a non-negative number", n.FullName(), maskV),
+				})
+			}
+		}
+
+		if trigger, _, _, isCond := parseReqCond(n.ReqTag()); isCond {
+			if _, ok := nGrp.Map()[trigger]; !ok {
+				failures = append(failures, ValidationFailure{
+					FieldName: n.FullName(),
+					Rule:      reqTag,
+					Message:   fmt.Sprintf("field '%v' 'req' condition references unknown field '%v'", n.FullName(), trigger),
+				})
+			}
+		}
+	}
+
+	// Rest fields are checked with an empty document so no values are set.
+	restKey := func(f reflect.StructField) (string, bool) { return f.Name, false }
+	if err := util.SetRest(nGrp.StructPtr(), map[string]interface{}{}, restKey, false); err != nil {
+		failures = append(failures, ValidationFailure{Rule: "rest", Message: err.Error()})
+	}
+
+	return failures
+}
+
+// checkConfig writes "config OK" to stderr and exits with code 0 when err is nil.
+// Otherwise the error is written to stderr and exits with code 1.
+//
+// When WithNonExiting is set "err" (or ErrConfigChecked when nil) is returned instead.
+func (g *GoConfig) checkConfig(err error) error {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "err: %v\n", err.Error())
+		if g.nonExiting {
+			return err
+		}
+		os.Exit(ExitCodeError)
+	}
+
+	fmt.Fprintln(os.Stderr, "config OK")
+	return g.stdFlagDone(ErrConfigChecked)
+}
+
+// stdFlagDone exits with code 0 after a standard flag action (such as --version).
+// When WithNonExiting is set "err" is returned instead.
+func (g *GoConfig) stdFlagDone(err error) error {
+	if g.nonExiting {
+		return err
+	}
+
+	os.Exit(0)
+	return nil
+}
+
+// validate runs post load validation and returns all validation failures
+// as a single error. Validation includes:
+// - checking that fields with the 'req:"true"' struct tag are not the zero value
+// - calling the field validators with the field values (see FieldValidate)
+// - calling Validate on app configs that implement the Validator interface
+func validate(nGrps []*node.Nodes, appCfgs []interface{}, fieldValidators []fieldValidator) error {
+	failures := make([]ValidationFailure, 0)
+	for _, nGrp := range nGrps {
+		for _, n := range nGrp.List() {
+			if msg := reqMissingMsg(n, nGrp); msg != "" {
+				failures = append(failures, ValidationFailure{
+					FieldName: n.FullName(),
+					Rule:      reqTag,
+					Message:   msg,
+				})
+			}
+			if msg := enumMsg(n, nGrp); msg != "" {
+				failures = append(failures, ValidationFailure{
+					FieldName: n.FullName(),
+					Rule:      enumTag,
+					Message:   msg,
+				})
+			}
+		}
+	}
+
+	for _, fv := range fieldValidators {
+		for _, nGrp := range nGrps {
+			n, ok := nGrp.Map()[fv.fieldName]
+			if !ok || isAnyIgnored(append(node.Parents(n, nGrp.Map()), n)) {
+				continue
+			}
+
+			if err := fv.fn(n.FieldValue.Interface()); err != nil {
+				failures = append(failures, ValidationFailure{
+					FieldName: n.FullName(),
+					Rule:      fieldValidateRule,
+					Message:   fmt.Sprintf("field '%v': %v", n.FullName(), err),
+				})
+			}
+		}
+	}
+
 	for _, appCfg := range appCfgs {
 		if val, ok := appCfg.(Validator); ok {
-			return val.Validate()
+			if err := val.Validate(); err != nil {
+				failures = append(failures, ValidationFailure{
+					Rule:    validateRule,
+					Message: err.Error(),
+				})
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return &ValidationErr{failures: failures}
+	}
+
+	return nil
+}
+
+// resolveSecrets replaces the value of each string field tagged with a secret resolver
+// tag name (with a "true" value) with the value resolved from the field value reference.
+// Empty field values are not resolved.
+func (g *GoConfig) resolveSecrets(nGrps []*node.Nodes) error {
+	for _, sr := range g.secretResolvers {
+		for _, nGrp := range nGrps {
+			for _, n := range nGrp.List() {
+				if !n.GetBoolTag(sr.tagName) || isAnyIgnored(append(node.Parents(n, nGrp.Map()), n)) {
+					continue
+				}
+
+				if !n.IsString() {
+					return &ParseErr{lName: sr.tagName, err: fmt.Errorf("field '%v' must be a string to resolve secrets", n.FullName())}
+				}
+
+				ref := n.String()
+				if ref == "" {
+					continue
+				}
+
+				val, err := sr.r.Resolve(ref)
+				if err != nil {
+					return &ParseErr{lName: sr.tagName, err: fmt.Errorf("field '%v': %w", n.FullName(), err)}
+				}
+
+				if err := n.SetFieldValue(val); err != nil {
+					return &ParseErr{lName: sr.tagName, err: err}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveExecs runs the command of each field with the 'exec' tag and sets the trimmed
+// command output (stdout) as the field value. Commands are split into arguments with
+// util.SplitArgs and run without a shell. The command stderr is included in
+// the returned error when the command fails.
+func resolveExecs(nGrps []*node.Nodes) error {
+	for _, nGrp := range nGrps {
+		for _, n := range nGrp.List() {
+			cmdLine := n.GetTag(execTag)
+			if cmdLine == "" || isAnyIgnored(append(node.Parents(n, nGrp.Map()), n)) {
+				continue
+			}
+
+			if n.IsStruct() || n.IsSlice() {
+				return &ParseErr{lName: execTag, err: fmt.Errorf("field '%v' must be a basic type to set from a command", n.FullName())}
+			}
+
+			out, err := runExec(cmdLine)
+			if err != nil {
+				return &ParseErr{lName: execTag, err: fmt.Errorf("field '%v': %w", n.FullName(), err)}
+			}
+
+			if err := n.SetFieldValue(out); err != nil {
+				return &ParseErr{lName: execTag, err: err}
+			}
+			n.SetMeta(sourceMeta, execTag)
+		}
+	}
+
+	return nil
+}
+
+// runExec runs the command line "cmdLine" and returns the trimmed stdout.
+func runExec(cmdLine string) (string, error) {
+	args, err := util.SplitArgs(cmdLine)
+	if err != nil {
+		return "", fmt.Errorf("command '%v': %w", cmdLine, err)
+	}
+	if len(args) == 0 {
+		return "", fmt.Errorf("command '%v': no command", cmdLine)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("command '%v': %w: %v", cmdLine, err, msg)
+		}
+		return "", fmt.Errorf("command '%v': %w", cmdLine, err)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// applyOverrides sets the field values of all the "Override" key=value pairs.
+func (g *GoConfig) applyOverrides(nGrps []*node.Nodes) error {
+	for _, o := range g.overrides {
+		pairs, err := parseOverride(o)
+		if err != nil {
+			return &ParseErr{lName: "override", err: err}
+		}
+
+		for _, p := range pairs {
+			nodes, err := findPathNodes(p[0], true, nGrps)
+			if err != nil {
+				return &ParseErr{lName: "override", err: err}
+			}
+			if len(nodes) == 0 {
+				return &ParseErr{lName: "override", err: fmt.Errorf("no field found by name '%v'", p[0])}
+			}
+
+			for _, n := range nodes {
+				if err := setNodeValue(n, p[1]); err != nil {
+					return &ParseErr{lName: "override", err: err}
+				}
+				n.SetMeta(sourceMeta, "override")
+			}
 		}
 	}
 
 	return nil
 }
 
+// overrideKeyRe matches an override list item starting with a "key=" field name.
+var overrideKeyRe = regexp.MustCompile(`^\s*[\w.]+\s*=`)
+
+// parseOverride parses a comma separated list of "key=value" pairs. A "," only starts
+// a new pair when followed by a "key=" field name. Otherwise it's part of the value so
+// comma separated slice values can be set. For example, "hosts=a,b,port=80" is the
+// pairs "hosts=a,b" and "port=80".
+//
+// Spaces around keys and values are trimmed and empty list items are ignored.
+func parseOverride(s string) ([][2]string, error) {
+	pairs := make([][2]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		if !overrideKeyRe.MatchString(item) {
+			if len(pairs) == 0 {
+				return nil, fmt.Errorf("invalid override '%v' expected key=value", strings.TrimSpace(item))
+			}
+
+			// Continuation of the previous value.
+			last := &pairs[len(pairs)-1]
+			last[1] += "," + strings.TrimSpace(item)
+			continue
+		}
+
+		kv := strings.SplitN(item, "=", 2)
+		pairs = append(pairs, [2]string{strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])})
+	}
+
+	return pairs, nil
+}
+
+// findPathNodes returns the nodes matching the full field name (case-insensitive
+// when "fold" is true) from all node groups. Numeric name segments are slice
+// indices (see node.Nodes.FindPath). For example, "Servers.0.Host".
+func findPathNodes(fieldName string, fold bool, nGrps []*node.Nodes) ([]*node.Node, error) {
+	nodes := make([]*node.Node, 0)
+	for _, nGrp := range nGrps {
+		n, err := nGrp.FindPath(fieldName, fold, node.Options{NoFollow: []string{"time.Time"}})
+		if err != nil {
+			return nil, err
+		}
+		if n != nil {
+			nodes = append(nodes, n)
+		}
+	}
+
+	return nodes, nil
+}
+
+// setNodeValue sets the node value from the string "s" handling time.Time and
+// slice (split with the 'sep' tag separator) fields.
+func setNodeValue(n *node.Node, s string) error {
+	switch {
+	case n.IsTime():
+		_, err := n.SetTime(s, n.GetTag("fmt"))
+		if err != nil {
+			return fmt.Errorf("field '%v': %w", n.FullName(), err)
+		}
+		return nil
+	case n.IsStructSlice() || (n.IsStruct() && !n.IsTime()):
+		return fmt.Errorf("field '%v' cannot be set from a single value", n.FullName())
+	case n.IsSlice():
+		sep := n.SliceSep()
+		if sep == "" {
+			sep = ","
+		}
+
+		vals := strings.Split(strings.Trim(s, "[]"), sep)
+		for i := range vals {
+			vals[i] = strings.TrimSpace(vals[i])
+		}
+		return n.SetSlice(vals)
+	}
+
+	return n.SetFieldValue(s)
+}
+
+// checkFieldTransforms returns an error if a field transform field name
+// does not match a field in any of the node groups or matches a struct
+// (or slice of structs) field.
+func (g *GoConfig) checkFieldTransforms(nGrps []*node.Nodes) error {
+	for _, ft := range g.fieldTransforms {
+		nodes := findNodes(ft.fieldName, nGrps)
+		if len(nodes) == 0 {
+			return errors.New("unable to transform field: no field found by name '" + ft.fieldName + "'")
+		}
+
+		for _, n := range nodes {
+			if n.IsStructSlice() || (n.IsStruct() && !n.IsTime()) {
+				return fmt.Errorf("unable to transform field '%v': struct fields cannot be transformed", n.FullName())
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkFieldValidators returns an error if a field validator field name
+// does not match a field in any of the node groups.
+func (g *GoConfig) checkFieldValidators(nGrps []*node.Nodes) error {
+	for _, fv := range g.fieldValidators {
+		if len(findNodes(fv.fieldName, nGrps)) == 0 {
+			return errors.New("unable to validate field: no field found by name '" + fv.fieldName + "'")
+		}
+	}
+
+	return nil
+}
+
+// applyFieldTransforms sets each field transform node to the value returned
+// by the transform func called with the node value string (see transformString).
+func (g *GoConfig) applyFieldTransforms(nGrps []*node.Nodes) error {
+	for _, ft := range g.fieldTransforms {
+		for _, n := range findNodes(ft.fieldName, nGrps) {
+			s := transformString(n)
+			val, err := ft.fn(s)
+			if err != nil {
+				return &ParseErr{lName: "transform", err: fmt.Errorf("field '%v': %w", n.FullName(), err)}
+			}
+			if val == s {
+				continue
+			}
+
+			if err := setNodeValue(n, val); err != nil {
+				return &ParseErr{lName: "transform", err: err}
+			}
+		}
+	}
+
+	return nil
+}
+
+// transformString returns the node value string in the form read by setNodeValue.
+// That is, time.Time values are formatted with the 'fmt' tag format and slice items
+// are joined with the 'sep' tag separator ("," by default).
+func transformString(n *node.Node) string {
+	switch {
+	case n.IsTime():
+		return n.TimeString(n.GetTag("fmt"))
+	case n.IsSlice():
+		sep := n.SliceSep()
+		if sep == "" {
+			sep = ","
+		}
+		return strings.Join(n.SliceString(), sep)
+	}
+
+	return n.String()
+}
+
+// selfRefRe matches a "${name}" field reference.
+var selfRefRe = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// resolveSelfRefs expands the "${name}" field references of all string fields
+// (see WithSelfReferences).
+func resolveSelfRefs(nGrps []*node.Nodes) error {
+	r := &refResolver{
+		fields: make(map[string]*node.Node),
+		done:   make(map[*node.Node]bool),
+	}
+
+	strNodes := make([]*node.Node, 0)
+	for _, nGrp := range nGrps {
+		for _, n := range nGrp.List() {
+			if isAnyIgnored(append(node.Parents(n, nGrp.Map()), n)) {
+				continue
+			}
+
+			// The first field is used when app configs have the same field.
+			if _, ok := r.fields[refKey(n.FullName())]; !ok {
+				r.fields[refKey(n.FullName())] = n
+			}
+			if n.IsString() {
+				strNodes = append(strNodes, n)
+			}
+		}
+	}
+
+	for _, n := range strNodes {
+		if err := r.resolve(n, nil); err != nil {
+			return &ParseErr{lName: "reference", err: err}
+		}
+	}
+
+	return nil
+}
+
+// refKey normalizes a field reference name so the field name
+// and config file name (i.e. "DataDir" and "data_dir") match.
+func refKey(name string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+}
+
+type refResolver struct {
+	fields map[string]*node.Node // Fields by reference key.
+	done   map[*node.Node]bool   // Expanded string fields.
+}
+
+// resolve expands the references of the string field "n" (expanding referenced string fields
+// first). "path" contains the fields being expanded and is used to detect reference cycles.
+func (r *refResolver) resolve(n *node.Node, path []*node.Node) error {
+	if !n.IsString() || r.done[n] {
+		return nil
+	}
+
+	for i, pn := range path {
+		if pn == n {
+			names := make([]string, 0, len(path)-i+1)
+			for _, cn := range append(path[i:], n) {
+				names = append(names, cn.FullName())
+			}
+			return fmt.Errorf("reference cycle '%v'", strings.Join(names, " -> "))
+		}
+	}
+	path = append(path, n)
+
+	var err error
+	val := selfRefRe.ReplaceAllStringFunc(n.String(), func(ref string) string {
+		if err != nil {
+			return ref
+		}
+
+		name := selfRefRe.FindStringSubmatch(ref)[1]
+		rn, ok := r.fields[refKey(name)]
+		if !ok {
+			err = fmt.Errorf("field '%v' references unknown field '%v'", n.FullName(), name)
+			return ref
+		}
+		if err = r.resolve(rn, path); err != nil {
+			return ref
+		}
+
+		return rn.ValueString()
+	})
+	if err != nil {
+		return err
+	}
+
+	r.done[n] = true
+	return n.SetFieldValue(val)
+}
+
+// findNodes returns the nodes matching the full field name from all node groups.
+func findNodes(fieldName string, nGrps []*node.Nodes) []*node.Node {
+	nodes := make([]*node.Node, 0)
+	for _, nGrp := range nGrps {
+		if n, ok := nGrp.Map()[fieldName]; ok {
+			nodes = append(nodes, n)
+		}
+	}
+
+	return nodes
+}
+
+// isAnyIgnored checks if any of the nodes has the 'ignore:"true"' or 'config:"ignore"'
+// struct tag.
+func isAnyIgnored(nodes []*node.Node) bool {
+	for _, n := range nodes {
+		if n.GetBoolTag("ignore") || n.GetTag("config") == "ignore" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reqMissingMsg returns the validation failure message when the node is required
+// and the field value is the zero value. Otherwise an empty string is returned.
+//
+// A node is required when it has the 'req:"true"' struct tag or a 'req' condition
+// (such as 'req:"TLSEnabled==true"') that evaluates to true. See "parseReqCond".
+//
+// Struct fields (excluding special structs like time.Time) and ignored
+// fields are never considered missing.
+func reqMissingMsg(n *node.Node, nGrp *node.Nodes) string {
+	trigger, op, val, isCond := parseReqCond(n.ReqTag())
+	if !isCond && !n.IsRequired() {
+		return ""
+	}
+
+	if n.IsStruct() && !n.IsTime() {
+		return ""
+	}
+
+	if isAnyIgnored(append(node.Parents(n, nGrp.Map()), n)) {
+		return ""
+	}
+
+	if !n.FieldValue.IsZero() {
+		return ""
+	}
+
+	if !isCond {
+		return fmt.Sprintf("field '%v' is required", n.FullName())
+	}
+
+	tn, ok := nGrp.Map()[trigger]
+	if !ok {
+		return fmt.Sprintf("field '%v' 'req' condition references unknown field '%v'", n.FullName(), trigger)
+	}
+
+	if (tn.ValueString() == val) != (op == "==") {
+		return ""
+	}
+
+	return fmt.Sprintf("field '%v' is required when field '%v' %v '%v'", n.FullName(), trigger, op, val)
+}
+
+// enumMsg returns the validation failure message when the node value is not one of
+// the 'enum' tag allowed values. Each slice item must be an allowed value. An empty string
+// is returned if the value is allowed.
+//
+// Zero values (fields not set) and ignored fields are not checked. Use the 'req' tag
+// to require a value.
+func enumMsg(n *node.Node, nGrp *node.Nodes) string {
+	allowed := n.EnumValues()
+	if len(allowed) == 0 || n.FieldValue.IsZero() {
+		return ""
+	}
+
+	if isAnyIgnored(append(node.Parents(n, nGrp.Map()), n)) {
+		return ""
+	}
+
+	vals := []string{n.ValueString()}
+	if n.IsSlice() && !n.IsStructSlice() {
+		vals = n.SliceString()
+	}
+
+	for _, v := range vals {
+		if itemIn(v, allowed) == "" {
+			if n.IsSecret() {
+				v = node.Redacted
+			}
+			return fmt.Sprintf("field '%v' value '%v' is not one of: %v", n.FullName(), v, strings.Join(allowed, "|"))
+		}
+	}
+
+	return ""
+}
+
+// parseReqCond parses a 'req' tag condition of the form "Field==value" or "Field!=value"
+// where "Field" is the full name (dot "." separated field path) of the trigger field.
+// "value" is compared to the trigger field value string (see "Node.ValueString").
+//
+// "isCond" is false if reqV is not a condition.
+func parseReqCond(reqV string) (trigger, op, val string, isCond bool) {
+	for _, op := range []string{"==", "!="} {
+		if idx := strings.Index(reqV, op); idx > 0 {
+			return strings.TrimSpace(reqV[:idx]), op, strings.TrimSpace(reqV[idx+len(op):]), true
+		}
+	}
+
+	return "", "", "", false
+}
+
 func (g *GoConfig) applyTagOverrides(nGrps []*node.Nodes) error {
+	// Type tags are applied first so struct field tags and
+	// field tag overrides take precedence.
+	for _, nGrp := range nGrps {
+		for _, n := range nGrp.List() {
+			for _, override := range g.typeTagOverrides {
+				if n.ValueType() == override.ValueType && n.GetTag(override.Tag) == "" {
+					n.SetTag(override.Tag, override.TagValue)
+				}
+			}
+		}
+	}
+
 	for _, nGrp := range nGrps {
 		for i, override := range g.tagOverrides {
-			err := nGrp.SetTag(override.FieldName, override.Tag, override.TagValue)
+			var err error
+			if override.cond == nil || override.cond() {
+				err = nGrp.SetTag(override.FieldName, override.Tag, override.TagValue)
+			} else if _, ok := nGrp.Map()[override.FieldName]; !ok {
+				err = errors.New("unable to set field tag: no field found by name '" + override.FieldName + "'")
+			}
 			if err != nil && !override.found {
 				g.tagOverrides[i].err = err
 			}
@@ -367,43 +2039,108 @@ func (g *GoConfig) applyTagOverrides(nGrps []*node.Nodes) error {
 	return nil
 }
 
+// applyDefaults copies the non-zero field values of the defaults struct
+// (if provided) into the matching app config fields. Fields are matched by
+// full name (dot "." separated field path).
+//
+// An error is returned if matching fields are not the same type.
+func (g *GoConfig) applyDefaults(nGrps []*node.Nodes) error {
+	if g.defaults == nil {
+		return nil
+	}
+
+	for _, nGrp := range nGrps {
+		if err := node.MergeNonZero(nGrp.StructPtr(), g.defaults); err != nil {
+			return fmt.Errorf("defaults: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // writeTemplate writes a generated template
 // either to a file (if file path provided) or stdout (otherwise).
 //
 // 'path' can either be a stand-along file extension or a file path (with file extension).
 //
 // If 'path' is empty then nil is returned. Otherwise either an error is returned or
-// the application exits with os.Exit(0).
-func (g *GoConfig) writeTemplate(name string, nGrps []*node.Nodes) error {
-	var err error
+// the application exits with os.Exit(0) (ErrTemplateGenerated is returned when
+// WithNonExiting is set).
+//
+// "cfgPath" is the config file a template is merged into (see WithTemplateMerge).
+func (g *GoConfig) writeTemplate(name, cfgPath string, nGrps []*node.Nodes) error {
 	if name == "" {
 		return nil
 	}
 
 	// choose unloader
-	lu, ok := g.lus[name]
+	lu, ok := g.loadLus[name]
 	if !ok {
 		return errors.New("unable to generate config template from unregistered name")
 	}
 	u := lu.Unloader
 
-	if u == nil {
-		return errors.New("template generation not supported for " + name)
+	if u == nil {
+		return errors.New("template generation not supported for " + name)
+	}
+
+	// merge into the existing config file (if enabled and supported)
+	if mu, ok := u.(load.MergeUnloader); ok && g.templateMerge {
+		b, err := g.readTemplateMergeFile(lu, cfgPath)
+		if err != nil {
+			return err
+		}
+
+		if b != nil {
+			b, err = mu.UnloadMerge(b, nGrps)
+			if err != nil {
+				return err
+			}
+
+			if _, err := os.Stdout.Write(b); err != nil {
+				return err
+			}
+
+			return g.stdFlagDone(ErrTemplateGenerated)
+		}
+	}
+
+	// unload and write (streaming if supported)
+	if su, ok := u.(load.StreamUnloader); ok {
+		if err := su.UnloadTo(os.Stdout, nGrps); err != nil {
+			return err
+		}
+	} else {
+		b, err := u.Unload(nGrps)
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stdout.Write(b); err != nil {
+			return err
+		}
 	}
 
-	// unload
-	b, err := u.Unload(nGrps)
-	if err != nil {
-		return err
+	return g.stdFlagDone(ErrTemplateGenerated)
+}
+
+// readTemplateMergeFile reads the config file "pth" to merge a generated template into.
+// nil is returned when no config file path is provided, the file does not exist or
+// the file extension is not one of the LoadUnloader extensions.
+func (g *GoConfig) readTemplateMergeFile(lu *LoadUnloader, pth string) ([]byte, error) {
+	if pth == "" || itemIn(strings.TrimPrefix(path.Ext(pth), "."), lu.FileExts) == "" {
+		return nil, nil
 	}
 
-	// write
-	if _, err := os.Stdout.Write(b); err != nil {
-		return err
+	b, err := ioutil.ReadFile(pth)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &ConfigFileErr{path: pth, err: err}
 	}
 
-	os.Exit(0)
-	return nil
+	return g.fileBytes(b), nil
 }
 
 // hasRegisteredExt checks if at least one LoadUnloader is registered with the provided
@@ -420,37 +2157,6 @@ func (g *GoConfig) hasRegisteredExt(ext string) bool {
 	return false
 }
 
-// loaderFromNameOrExt locates a load.Loader from a name and file extension (if provided).
-// If one or more exts are defined on the LoadUnloader then an ext is required for a match.
-// Otherwise match on the name.
-//
-// It's not expected that a match is always found since one particular file
-// extension can be used over another at runtime.
-func (g *GoConfig) loaderFromNameOrExt(name, ext string) load.Loader {
-	for _, lu := range g.lus {
-
-		// Match on name if no file exts defined.
-		if len(lu.FileExts) == 0 {
-			if lu.Name == name {
-				return lu.Loader
-			}
-
-			continue
-		}
-
-		// Match on ext if file exts are defined.
-		if len(lu.FileExts) > 0 {
-			for _, lExt := range lu.FileExts {
-				if lExt == ext {
-					return lu.Loader
-				}
-			}
-		}
-	}
-
-	return nil
-}
-
 func (g *GoConfig) loaderFromExt(ext string) (load.Loader, error) {
 	for _, lu := range g.lus {
 		for _, lExt := range lu.FileExts {
@@ -506,34 +2212,199 @@ func (ce ConfigExtNotFoundErr) Error() string {
 	return fmt.Sprintf("filename extension not found for path '%v'", ce.path)
 }
 
+// ConfigFileErr is returned when the config file cannot be read. For example,
+// when the file does not exist.
+type ConfigFileErr struct {
+	path string
+	err  error
+}
+
+func (ce ConfigFileErr) Error() string {
+	return ce.err.Error()
+}
+
+func (ce ConfigFileErr) Unwrap() error {
+	return ce.err
+}
+
+// ParseErr is returned when a loader fails to read in the config values. For example,
+// when a config file is malformed or a value cannot be converted to the field type.
+type ParseErr struct {
+	lName string // loader name
+	err   error
+}
+
+func (pe ParseErr) Error() string {
+	return fmt.Sprintf("%v: %v", pe.lName, pe.err.Error())
+}
+
+func (pe ParseErr) Unwrap() error {
+	return pe.err
+}
+
+// ValidationErr is returned when post load validation fails. It contains
+// all the validation failures. Use errors.As to inspect the failures:
+//
+//	var ve *config.ValidationErr
+//	if errors.As(err, &ve) {
+//	    for _, f := range ve.Failures() {
+//	        fmt.Println(f.FieldName, f.Rule)
+//	    }
+//	}
+type ValidationErr struct {
+	failures []ValidationFailure
+}
+
+func (ve ValidationErr) Error() string {
+	msgs := make([]string, len(ve.failures))
+	for i, f := range ve.failures {
+		msgs[i] = f.Message
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Failures returns the validation failures in the order found.
+func (ve ValidationErr) Failures() []ValidationFailure {
+	return ve.failures
+}
+
+// StructErr is returned by Check when config struct definitions have problems. It
+// contains all the problems found as failures with the "Rule" set to the struct tag
+// or feature at fault (such as "env", "flag", "req" or "rest").
+type StructErr struct {
+	failures []ValidationFailure
+}
+
+func (se StructErr) Error() string {
+	msgs := make([]string, len(se.failures))
+	for i, f := range se.failures {
+		msgs[i] = f.Message
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Failures returns the struct problems in the order found.
+func (se StructErr) Failures() []ValidationFailure {
+	return se.failures
+}
+
+// ValidationFailure is a single post load validation failure.
+type ValidationFailure struct {
+	// FieldName is the full (dot "." separated) field name. Empty for
+	// Validator failures.
+	FieldName string
+
+	// Rule is the failed rule. "req" for required fields (including 'req' conditions),
+	// "FieldValidate" for FieldValidate failures and "Validate" for Validator failures.
+	Rule string
+
+	// Message is the human readable failure message.
+	Message string
+}
+
+// Standard flag action errors returned by Load instead of exiting when WithNonExiting is set.
+var (
+	ErrHelpRequested     = errors.New("help requested")      // --help,-h
+	ErrVersionRequested  = errors.New("version requested")   // --version,-v
+	ErrTemplateGenerated = errors.New("template generated")  // --gen,-g and --gen-resolved
+	ErrValuesShown       = errors.New("config values shown") // --show
+	ErrConfigChecked     = errors.New("config checked")      // --check (valid config)
+)
+
+// isStdFlagDone returns true when err is a standard flag action error (see WithNonExiting).
+func isStdFlagDone(err error) bool {
+	for _, done := range []error{ErrHelpRequested, ErrVersionRequested, ErrTemplateGenerated, ErrValuesShown, ErrConfigChecked} {
+		if errors.Is(err, done) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Exit codes used by LoadOrDie by error category.
+//
+// Note: invalid command line flags exit with code 2 (the standard flag package behavior).
+const (
+	ExitCodeError          = 1 // generic failure
+	ExitCodeConfigFile     = 3 // config file not found or unreadable (ConfigFileErr)
+	ExitCodeLoaderNotFound = 4 // no loader for the config file extension (LoaderNotFoundErr, ConfigExtNotFoundErr)
+	ExitCodeParse          = 5 // config values could not be parsed (ParseErr)
+	ExitCodeValidation     = 6 // post load validation failure (ValidationErr)
+)
+
+// ExitCode returns the exit code for the error category of err. Returns 0 if err is nil
+// or a standard flag action error (such as ErrHelpRequested).
+func ExitCode(err error) int {
+	var (
+		fileErr  *ConfigFileErr
+		lnfErr   *LoaderNotFoundErr
+		extErr   *ConfigExtNotFoundErr
+		parseErr *ParseErr
+		validErr *ValidationErr
+	)
+
+	switch {
+	case err == nil, isStdFlagDone(err):
+		return 0
+	case errors.As(err, &fileErr):
+		return ExitCodeConfigFile
+	case errors.As(err, &lnfErr), errors.As(err, &extErr):
+		return ExitCodeLoaderNotFound
+	case errors.As(err, &parseErr):
+		return ExitCodeParse
+	case errors.As(err, &validErr):
+		return ExitCodeValidation
+	default:
+		return ExitCodeError
+	}
+}
+
 // showVersion will write the version to stderr and exit.
-func (g *GoConfig) showVersion() {
+func (g *GoConfig) showVersion() error {
 	fmt.Fprintln(os.Stderr, g.version)
 
-	os.Exit(0)
+	return g.stdFlagDone(ErrVersionRequested)
 }
 
 func (g *GoConfig) prepStdFlags(nGrp *node.Nodes) {
 	// "config" standard flag.
 	exts := g.allExts()
 	if len(exts) > 0 {
-		nGrp.SetTag("ConfigPath", "help", fmt.Sprintf(cfgPathHelp, strings.Join(exts, "|")))
+		help := fmt.Sprintf(cfgPathHelp, strings.Join(exts, "|"))
+		if g.cfgPathEnv != "" {
+			help += fmt.Sprintf(" (env: %s)", g.cfgPathEnv)
+		}
+		nGrp.SetTag("ConfigPath", "help", help)
+		nGrp.SetTag("ConfigURL", "help", cfgURLHelp)
+		nGrp.SetTag("ConfigType", "help", fmt.Sprintf(cfgTypeHelp, strings.Join(exts, "|")))
 	} else {
 		nGrp.SetTag("ConfigPath", "flag", "-")
+		nGrp.SetTag("ConfigURL", "flag", "-")
+		nGrp.SetTag("ConfigType", "flag", "-")
 	}
 
 	// "gen" standard flag.
 	allNames := g.allNames()
 	if len(allNames) > 0 {
 		nGrp.SetTag("Gen", "help", fmt.Sprintf(genConfigHelp, strings.Join(allNames, "|")))
+		nGrp.SetTag("GenResolved", "help", fmt.Sprintf(genResolvedHelp, strings.Join(allNames, "|")))
 	} else {
 		nGrp.SetTag("Gen", "flag", "-") // no exts - ignore
+		nGrp.SetTag("GenResolved", "flag", "-")
 	}
 
 	// "version" standard flag.
 	if g.version == "" {
 		nGrp.SetTag("ShowVersion", "flag", "-")
 	}
+
+	// Individually disabled standard flags.
+	for fieldName := range g.stdFlgsOff {
+		nGrp.SetTag(fieldName, "flag", "-")
+	}
 }
 
 func Show() error {
@@ -564,14 +2435,14 @@ func (g *GoConfig) parsePath(pth string) (fpth, ext string, err error) {
 		return "", "", nil
 	}
 
-	ext = path.Ext(pth)
+	ext = strings.TrimPrefix(path.Ext(pth), ".")
 	if ext == "" {
 		// maybe pth is just an extension.
 		if g.isValidExt(pth) {
 			return "", pth, nil
 		}
 
-		return "", "", errors.New("invalid file path")
+		return "", "", &ConfigExtNotFoundErr{path: pth}
 	}
 
 	return pth, ext, nil
@@ -595,28 +2466,113 @@ func (g *GoConfig) ShowValues() error {
 	return g.FShowValues(os.Stderr)
 }
 
+// FShowValues writes the values to w. If the show options ShowVersion is
+// true and a version is set then the version is written first.
 func (g *GoConfig) FShowValues(w io.Writer) error {
+	if g.showOptions.ShowVersion && g.version != "" {
+		if _, err := fmt.Fprintln(w, g.version); err != nil {
+			return err
+		}
+	}
+
 	b := g.showRenderer.Render()
 	_, err := fmt.Fprintln(w, string(b))
 
 	return err
 }
 
-// loadAll iterates through the "with" list and loads
-// the config values into cfgs[1:] where cfgs[1:] are all the
-// app config node groups and nGrps[0] is the node group for the standard config (for standard flags).
+// HelpText returns the flag help menu (the same menu printed by --help) of the app
+// config struct pointers "appCfgs" without loading any values. Useful to show the config
+// help inside the usage of an embedding command. The app configs of the most recent load
+// are used when none are provided.
 //
-// Expects "nGrps" to contain the standard config node group first followed by application
-// config node groups.
-func (g *GoConfig) loadAll(fPath string, nGrps []*node.Nodes) error {
-	// read in config file
-	var cfgB []byte
-	var err error
-	if fPath != "" {
-		cfgB, err = ioutil.ReadFile(fPath)
+// Field tag overrides (see FieldHelp and FieldTag) and the flag options are applied. The
+// listed default values are the current field values. An empty string is returned when
+// flags and standard flags are disabled.
+func (g *GoConfig) HelpText(appCfgs ...interface{}) (string, error) {
+	if len(appCfgs) == 0 {
+		for _, nGrp := range g.nGrps {
+			appCfgs = append(appCfgs, nGrp.StructPtr())
+		}
+	}
+	if len(appCfgs) == 0 {
+		return "", fmt.Errorf("nothing to generate help from")
+	}
+
+	if err := util.AreStructPointers(appCfgs...); err != nil {
+		return "", err
+	}
+
+	cfgs := make([]interface{}, 0)
+	if !g.stdFlgsDisabled {
+		cfgs = append(cfgs, g.stdFlgs)
+	}
+	cfgs = append(cfgs, appCfgs...)
+	allNGrps := node.MakeAllNodes(node.Options{
+		NoFollow:  []string{"time.Time"},
+		NoInitNil: g.noInitNil,
+	}, cfgs...)
+
+	stdNGrp := make([]*node.Nodes, 0)
+	nGrps := allNGrps
+	if !g.stdFlgsDisabled {
+		stdNGrp = allNGrps[0:1]
+		nGrps = allNGrps[1:]
+		g.prepStdFlags(stdNGrp[0])
+	}
+
+	if err := g.applyTagOverrides(nGrps); err != nil {
+		return "", err
+	}
+	g.prepLoaders()
+
+	// Record env names for the help menu.
+	if g.loadFlgOptions.ShowEnvInHelp && itemIn("env", g.with) == "env" {
+		if el, ok := g.loadLus["env"].Loader.(*env.EnvLoader); ok {
+			if err := el.RecordNames(nGrps); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	flgNGrps := g.flagNGrps(stdNGrp, nGrps)
+	if len(flgNGrps) == 0 {
+		return "", nil
+	}
+
+	return flg.NewLoader(g.loadFlgOptions).Help(flgNGrps)
+}
+
+// JSONSchema generates a draft-07 JSON Schema describing the app config(s) of
+// the most recent load. See "schema.Generate" for how the schema is derived.
+func (g *GoConfig) JSONSchema() ([]byte, error) {
+	if len(g.nGrps) == 0 {
+		return nil, fmt.Errorf("nothing loaded to generate a schema from")
+	}
+
+	return schema.Generate(g.nGrps)
+}
+
+// loadAll reads the config at the --config-url URL and the config file at fPath
+// (if provided) and loads the config values into the app config node groups "nGrps".
+// The config file is loaded after the URL config.
+//
+// "stdNGrp" is the node group for the standard config (for standard flags) and
+// is empty when standard flags are disabled.
+func (g *GoConfig) loadAll(fPath string, stdNGrp, nGrps []*node.Nodes) error {
+	g.cfgFileLoaded = false
+	cfgURL := g.stdFlgs.ConfigURL
+	if fPath == "" && cfgURL == "" && g.requireCfgFile {
+		return &ConfigFileErr{err: errors.New("config file required but no config file path provided")}
+	}
+
+	srcs := make([]cfgSource, 0)
+	if cfgURL != "" {
+		src, err := g.fetchConfig(cfgURL, g.stdFlgs.ConfigType)
 		if err != nil {
 			return err
 		}
+		srcs = append(srcs, src)
 	}
 
 	_, pthExt, err := g.parsePath(fPath)
@@ -624,36 +2580,276 @@ func (g *GoConfig) loadAll(fPath string, nGrps []*node.Nodes) error {
 		return err
 	}
 
-	// Extension required if fPath is provided.
-	if len(fPath) > 0 && len(pthExt) == 0 {
-		return &LoaderNotFoundErr{lExt: pthExt}
+	// Extension required if fPath is provided.
+	if len(fPath) > 0 && len(pthExt) == 0 {
+		return &LoaderNotFoundErr{lExt: pthExt}
+	}
+
+	// Extension must match at least one loader (when present).
+	if len(pthExt) > 0 {
+		if !g.hasRegisteredExt(pthExt) {
+			return &LoaderNotFoundErr{lExt: pthExt}
+		}
+	}
+
+	// read in config file
+	if fPath != "" {
+		cfgB, err := ioutil.ReadFile(fPath)
+		if err != nil {
+			return &ConfigFileErr{path: fPath, err: err}
+		}
+		srcs = append(srcs, cfgSource{ext: pthExt, b: cfgB})
+	}
+
+	if err := g.loadBytes(srcs, stdNGrp, nGrps); err != nil {
+		return err
+	}
+
+	g.cfgFileLoaded = len(srcs) > 0
+	return nil
+}
+
+// fetchConfig reads the config at the http(s) URL "rawURL". The config type "typ"
+// selects the file loader. When empty the URL path extension is used.
+//
+// The URL user info (i.e. "user:pass@") is not included in returned errors.
+func (g *GoConfig) fetchConfig(rawURL, typ string) (cfgSource, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return cfgSource{}, &ConfigFileErr{err: fmt.Errorf("invalid config url: %w", unwrapURLErr(err))}
+	}
+	safeURL := *u
+	safeURL.User = nil
+	dispURL := safeURL.String()
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return cfgSource{}, &ConfigFileErr{path: dispURL, err: fmt.Errorf("unsupported scheme '%v' for config url '%v'", u.Scheme, dispURL)}
+	}
+
+	ext := strings.Trim(strings.TrimSpace(typ), ".")
+	if ext == "" {
+		ext = strings.TrimPrefix(path.Ext(u.Path), ".")
+	}
+	if ext == "" {
+		return cfgSource{}, &ConfigExtNotFoundErr{path: dispURL}
+	}
+	if !g.hasRegisteredExt(ext) {
+		return cfgSource{}, &LoaderNotFoundErr{lExt: ext}
+	}
+
+	resp, err := httpClient.Get(u.String())
+	if err != nil {
+		return cfgSource{}, &ConfigFileErr{path: dispURL, err: fmt.Errorf("config url '%v': %w", dispURL, unwrapURLErr(err))}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return cfgSource{}, &ConfigFileErr{path: dispURL, err: fmt.Errorf("unexpected status '%v' for config url '%v'", resp.Status, dispURL)}
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return cfgSource{}, &ConfigFileErr{path: dispURL, err: err}
+	}
+
+	return cfgSource{ext: ext, b: b}, nil
+}
+
+// unwrapURLErr returns the underlying error of a *url.Error (which includes the
+// request URL) or "err" as-is.
+func unwrapURLErr(err error) error {
+	var uErr *url.Error
+	if errors.As(err, &uErr) {
+		return uErr.Err
+	}
+
+	return err
+}
+
+// cfgSource is config file bytes and the file extension selecting the file loader.
+type cfgSource struct {
+	ext string
+	b   []byte
+}
+
+// loadBytes iterates through the "with" list and loads the config values
+// into nGrps. Only the file loaders matching a config source extension are used
+// (once for each matching source in "srcs" order) along with all the loaders that
+// are not file based.
+//
+// The inline config env value (see WithInlineConfigEnv) is loaded before the "with" list.
+//
+// The flag loader also receives the standard flag node group "stdNGrp" so that
+// standard flags (such as --config) are recognized when parsing.
+func (g *GoConfig) loadBytes(srcs []cfgSource, stdNGrp, nGrps []*node.Nodes) error {
+	if err := g.loadInlineConfig(nGrps); err != nil {
+		return err
+	}
+
+	for _, w := range g.with {
+		// Predicates are checked right before the loader would run so values
+		// from loaders earlier in the "with" list are available.
+		if enabled, ok := g.loaderEnabled[w]; ok && !enabled() {
+			continue
+		}
+
+		lu, ok := g.loadLus[w]
+		if !ok {
+			continue
+		}
+
+		grps := nGrps
+		if w == "flag" {
+			grps = append(append([]*node.Nodes{}, stdNGrp...), nGrps...)
+		}
+
+		// Loaders that are not file based don't receive config file bytes.
+		if len(lu.FileExts) == 0 {
+			if err := g.loadWith(w, lu.Loader, nil, nGrps, grps); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, src := range srcs {
+			if itemIn(src.ext, lu.FileExts) == "" {
+				continue
+			}
+
+			if err := g.loadWith(w, lu.Loader, g.fileBytes(src.b), nGrps, grps); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// utf8BOM is the UTF-8 byte order mark some editors write at the start of files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// fileBytes returns the config file bytes "b" with a leading UTF-8 BOM removed and
+// "\r\n" line endings normalized to "\n" when lenient file parsing is enabled (see
+// WithLenientFileParsing). Otherwise "b" is returned as-is.
+func (g *GoConfig) fileBytes(b []byte) []byte {
+	if !g.lenientFiles {
+		return b
+	}
+
+	b = bytes.TrimPrefix(b, utf8BOM)
+	return bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+}
+
+// loadInlineConfig loads the value of the inline config env variable (if set) into
+// "nGrps" with the file loader of the inline config format.
+func (g *GoConfig) loadInlineConfig(nGrps []*node.Nodes) error {
+	if g.inlineCfgEnv == "" {
+		return nil
+	}
+
+	v := os.Getenv(g.inlineCfgEnv)
+	if v == "" {
+		return nil
+	}
+
+	ext := strings.Trim(strings.TrimSpace(g.inlineCfgFormat), ".")
+	for _, lu := range g.lus {
+		if itemIn(ext, lu.FileExts) != "" {
+			return g.loadWith(lu.Name, lu.Loader, []byte(v), nGrps, nGrps)
+		}
+	}
+
+	return &LoaderNotFoundErr{lExt: ext}
+}
+
+// loadWith loads the config file bytes "b" (if any) into "grps" with the loader
+// "l" named "name". The value sources of "nGrps" are recorded.
+func (g *GoConfig) loadWith(name string, l load.Loader, b []byte, nGrps, grps []*node.Nodes) error {
+	// Select the root key section of the config file (if set).
+	if len(b) > 0 && g.fileRootKey != "" {
+		var err error
+		b, err = g.rootKeySection(name, l, b)
+		if err != nil {
+			return &ParseErr{lName: name, err: err}
+		}
+	}
+
+	// Select the profile section of the config file (if enabled).
+	if len(b) > 0 && g.profile != "" {
+		var err error
+		b, err = g.profileSection(name, l, b)
+		if err != nil {
+			return &ParseErr{lName: name, err: err}
+		}
+	}
+
+	// File loaders decode the whole config so ignored
+	// field values are restored after loading.
+	restoreIgnored := func() {}
+	if len(b) > 0 {
+		restoreIgnored = snapshotIgnored(nGrps)
+	}
+
+	recordSources := trackSources(nGrps)
+	if err := l.Load(b, grps); err != nil {
+		return &ParseErr{lName: name, err: err}
+	}
+	restoreIgnored()
+	recordSources(name)
+
+	return nil
+}
+
+// rootKeySection returns the file root key section of the config file bytes "b"
+// read by the loader "name". Each dot "." separated root key level is selected in order.
+func (g *GoConfig) rootKeySection(name string, l load.Loader, b []byte) ([]byte, error) {
+	sl, ok := l.(load.SectionLoader)
+	if !ok {
+		return nil, fmt.Errorf("loader '%v' does not support a file root key", name)
+	}
+
+	for _, key := range strings.Split(g.fileRootKey, ".") {
+		var err error
+		if b, err = sl.Section(b, key); err != nil {
+			return nil, fmt.Errorf("root key: %w", err)
+		}
+	}
+
+	return b, nil
+}
+
+// profileSection returns the profile section of the config file bytes "b"
+// read by the loader "name".
+func (g *GoConfig) profileSection(name string, l load.Loader, b []byte) ([]byte, error) {
+	profile := g.profile
+	if v := os.Getenv(g.profile); v != "" {
+		profile = v
 	}
 
-	// Extension must match at least one loader (when present).
-	if len(pthExt) > 0 {
-		if !g.hasRegisteredExt(pthExt) {
-			return &LoaderNotFoundErr{lExt: pthExt}
-		}
+	sl, ok := l.(load.SectionLoader)
+	if !ok {
+		return nil, fmt.Errorf("loader '%v' does not support profiles", name)
 	}
 
-	// Load all.
-	for _, w := range g.with {
-		if l := g.loaderFromNameOrExt(w, pthExt); l != nil {
-			if err := l.Load(cfgB, nGrps); err != nil {
-				return err
-			}
-		}
+	b, err := sl.Section(b, profile)
+	if err != nil {
+		return nil, fmt.Errorf("profile: %w", err)
 	}
 
-	return nil
+	return b, nil
 }
 
 // LoadOrDie calls Load and prints an error message and exits if there is an error.
+//
+// The exit code depends on the error category. See ExitCode.
 func (g *GoConfig) LoadOrDie(appCfg ...interface{}) {
 	err := g.Load(appCfg...)
+	if isStdFlagDone(err) {
+		os.Exit(0)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "err: %v\n", err.Error())
-		os.Exit(1)
+		os.Exit(ExitCode(err))
 	}
 }
 
@@ -706,6 +2902,11 @@ func loadUnloaderNames(lus map[string]*LoadUnloader) []string {
 // extensions can be registered. Space characters and leading periods (".") are trimmed. Therefore you could
 // also provide ".yaml" or "yaml". It doesn't matter.
 //
+// A file extension can only be claimed by one LoadUnloader. Registering a file extension already
+// claimed by a LoadUnloader of a different name will panic. So will registering a name that
+// matches a file extension claimed by a different LoadUnloader. To use a custom implementation for
+// a standard file extension register it using the standard name (i.e. "yaml").
+//
 // Because of its special nature, overriding "flag" is not allowed. Attempting to override "flag"
 // will panic. Flags can be disabled by taking advantage of the "With" method and omitting "flag".
 //
@@ -719,6 +2920,11 @@ func (g *GoConfig) RegisterLoadUnloader(loadUnloader *LoadUnloader) *GoConfig {
 	// sanitize extensions
 	loadUnloader = sanitizeExts(loadUnloader)
 
+	// check for file extension collisions
+	if err := g.validateExtCollisions(loadUnloader); err != nil {
+		panic(err.Error())
+	}
+
 	// update list
 	g.lus[loadUnloader.Name] = loadUnloader
 
@@ -748,6 +2954,35 @@ func validateLoadUnloader(lu *LoadUnloader) error {
 	return nil
 }
 
+// validateExtCollisions checks that the LoadUnloader file extensions are not already claimed by
+// another registered LoadUnloader and that the LoadUnloader name does not shadow a file extension
+// of another registered LoadUnloader. Otherwise config file extension resolution would be ambiguous.
+//
+// A registered LoadUnloader of the same name is not checked since it will be replaced.
+func (g *GoConfig) validateExtCollisions(lu *LoadUnloader) error {
+	for _, regLU := range g.lus {
+		if regLU.Name == lu.Name {
+			continue
+		}
+
+		for _, regExt := range regLU.FileExts {
+			if regExt == lu.Name {
+				return fmt.Errorf("loadunloader name '%v' collides with a file extension registered by '%v'",
+					lu.Name, regLU.Name)
+			}
+
+			for _, ext := range lu.FileExts {
+				if ext == regExt {
+					return fmt.Errorf("file extension '%v' of loadunloader '%v' is already registered by '%v'",
+						ext, lu.Name, regLU.Name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 func appendUnique(withList []string, name string) []string {
 	for _, listName := range withList {
 		if listName == name {
@@ -764,8 +2999,7 @@ func sanitizeExts(lu *LoadUnloader) *LoadUnloader {
 	}
 
 	for i, ext := range lu.FileExts {
-		lu.FileExts[i] = strings.TrimSpace(ext)
-		lu.FileExts[i] = strings.Trim(ext, ".")
+		lu.FileExts[i] = strings.Trim(strings.TrimSpace(ext), ".")
 	}
 
 	return lu
@@ -798,6 +3032,175 @@ func (g *GoConfig) DisableStdFlags() *GoConfig {
 	return g
 }
 
+// DisableConfigFlag disables only the --config standard flag. The config path
+// can still be provided with SetConfigPath or ConfigPathEnv.
+func (g *GoConfig) DisableConfigFlag() *GoConfig {
+	return g.disableStdFlag("ConfigPath")
+}
+
+// DisableGenFlag disables only the --gen standard flag.
+func (g *GoConfig) DisableGenFlag() *GoConfig {
+	return g.disableStdFlag("Gen")
+}
+
+// DisableGenResolvedFlag disables only the --gen-resolved standard flag.
+func (g *GoConfig) DisableGenResolvedFlag() *GoConfig {
+	return g.disableStdFlag("GenResolved")
+}
+
+// DisableShowFlag disables only the --show standard flag.
+func (g *GoConfig) DisableShowFlag() *GoConfig {
+	return g.disableStdFlag("ShowValues")
+}
+
+// DisableVersionFlag disables only the --version standard flag.
+func (g *GoConfig) DisableVersionFlag() *GoConfig {
+	return g.disableStdFlag("ShowVersion")
+}
+
+// DisableCheckFlag disables only the --check standard flag.
+func (g *GoConfig) DisableCheckFlag() *GoConfig {
+	return g.disableStdFlag("CheckConfig")
+}
+
+// DisableConfigURLFlag disables only the --config-url and --config-type standard flags.
+func (g *GoConfig) DisableConfigURLFlag() *GoConfig {
+	g.disableStdFlag("ConfigURL")
+	return g.disableStdFlag("ConfigType")
+}
+
+// disableStdFlag disables the standard flag of the stdFlgs field "fieldName".
+func (g *GoConfig) disableStdFlag(fieldName string) *GoConfig {
+	if g.stdFlgsOff == nil {
+		g.stdFlgsOff = make(map[string]bool)
+	}
+	g.stdFlgsOff[fieldName] = true
+	return g
+}
+
+// WithDefaults provides a struct (or struct pointer) of default values kept separate from
+// the config struct(s) passed to Load. Before any loader runs the non-zero default field values are
+// copied into the config fields with the same full name (dot "." separated field path) and
+// are recorded as the default values when showing the config.
+//
+// The defaults value is not modified. Load returns an error if a matching field is not
+// the same type.
+func (g *GoConfig) WithDefaults(defaults interface{}) *GoConfig {
+	g.defaults = defaults
+	return g
+}
+
+// WithSecretResolver registers a SecretResolver for fields with the 'tagName' struct tag set
+// to "true". After all loaders run, the loaded field value is used as the secret reference and
+// is replaced with the resolved value. For example:
+//
+//	type options struct {
+//	    DBPassword string `awssm:"true"` // DB_PASSWORD=prod/db/password
+//	}
+//
+//	config.WithSecretResolver("awssm", myAWSResolver).Load(&appCfg)
+//
+// Only string fields can be resolved. Resolvers are run in the order registered.
+//
+// Note: resolved values are displayed by --show unless the field has the 'show:"false"' tag.
+func (g *GoConfig) WithSecretResolver(tagName string, r SecretResolver) *GoConfig {
+	g.secretResolvers = append(g.secretResolvers, secretResolver{tagName: tagName, r: r})
+	return g
+}
+
+// EnableExecSources enables setting field values from command output with the 'exec' struct
+// tag. After all loaders (and secret resolvers) run, the command of each field with the tag is
+// run and the trimmed output (stdout) is set as the field value. For example:
+//
+//	type options struct {
+//	    DBPassword string `exec:"vault kv get -field=password secret/db" secret:"true"`
+//	}
+//
+//	config.EnableExecSources().Load(&appCfg)
+//
+// Commands are split into arguments with shell-like quoting but are not run by a shell.
+// Load returns an error with the command stderr when a command fails.
+//
+// The 'exec' tag is ignored unless enabled since running commands from struct tags
+// should be an explicit choice of the app.
+func (g *GoConfig) EnableExecSources() *GoConfig {
+	g.execSources = true
+	return g
+}
+
+// WithLoader sets a predicate for the loader "name" (in the "With" list) that is checked
+// at Load time right before the loader runs. The loader is skipped when "enabled"
+// returns false.
+//
+// Since loaders run in "With" order a value loaded by an earlier loader can decide if
+// a later loader runs. For example, only load env values in production:
+//
+//	config.With("flag", "env").WithLoader("env", func() bool {
+//	    return appCfg.Env == "production"
+//	})
+//
+// Note: the standard flags (such as --help) are always handled.
+func (g *GoConfig) WithLoader(name string, enabled func() bool) *GoConfig {
+	if g.loaderEnabled == nil {
+		g.loaderEnabled = make(map[string]func() bool)
+	}
+	g.loaderEnabled[name] = enabled
+	return g
+}
+
+// NoInitNil will leave nil struct pointers as nil after loading when no values
+// were loaded into any of the struct fields.
+//
+// By default nil struct pointers are always initialized so that optional sub-configs
+// (for example "*DB") are never nil after calling Load.
+func (g *GoConfig) NoInitNil() *GoConfig {
+	g.noInitNil = true
+	return g
+}
+
+// WithSliceSplitter sets how env and flag slice values are split into items. For example,
+// to parse shell words or CSV. "sep" is the field separator ("," by default) and "isString"
+// is true for fields with the ",string" env or flag tag option.
+//
+// The default splitting splits on "sep" and trims spaces and surrounding "[]" (and quotes
+// when "isString" is true). Not used for slices with the 'sep:",json"' option.
+func (g *GoConfig) WithSliceSplitter(fn func(raw, sep string, isString bool) []string) *GoConfig {
+	g.sliceSplitter = fn
+	return g
+}
+
+// WithCollisionStrategy sets how env and flag names generated by more than one field are
+// resolved. The same strategy is used for env and flag names. Options are:
+// - "error" // Load returns an error (default).
+// - "prefix-disambiguate" // the colliding field names include the names of all parents (including
+// 'omitprefix' parents). For example, "DB_HOST" and "--db-host" instead of "HOST" and "--host".
+// - "first-wins" // the first field keeps the name and the later field is not loaded by env or flag.
+//
+// Load returns an error for an unknown strategy or when a disambiguated name is also taken.
+func (g *GoConfig) WithCollisionStrategy(strategy string) *GoConfig {
+	g.collisionStrategy = strategy
+	return g
+}
+
+// WithIndexedEnvSlices enables reading slice fields from numbered env variables where each
+// item has its own variable. For example, "HOSTS_0", "HOSTS_1", etc. (see env.EnvLoader.WithIndexedSlices).
+//
+// Applied to the registered "env" loader when it is the go-config env loader.
+func (g *GoConfig) WithIndexedEnvSlices() *GoConfig {
+	g.indexedEnv = true
+	return g
+}
+
+// WithWarnUnusedEnv enables writing a warning to "w" for each env variable starting with
+// the global prefix (see NewWithPrefix) that is not read by any field. Useful to catch
+// misspelled env names. The ConfigPathEnv and WithInlineConfigEnv variables are not reported.
+//
+// Applied to the registered "env" loader when it is the go-config env loader.
+func (g *GoConfig) WithWarnUnusedEnv(w io.Writer) *GoConfig {
+	g.unusedEnvOut = w
+	return g
+}
+
 // FieldHelp allows adding a struct field help tag at runtime. Field names are dot "." separated
 // values when referring to struct fields in struct fields.
 //
@@ -811,6 +3214,27 @@ func (g *GoConfig) FieldHelp(fieldName, helpTxt string) *GoConfig {
 	return g
 }
 
+// IgnoreField ignores the field "fieldName" (and its sub fields) in all loaders by
+// setting the 'ignore:"true"' tag. Field names are dot "." separated values when
+// referring to struct fields in struct fields.
+func (g *GoConfig) IgnoreField(fieldName string) *GoConfig {
+	return g.FieldTag(fieldName, "ignore", "true")
+}
+
+// IgnoreFieldIf behaves like IgnoreField when "cond" returns true. "cond" is
+// called when "Load" is called. For example, to ignore a field on some platforms:
+//
+//	config.IgnoreFieldIf("Systemd", func() bool { return runtime.GOOS != "linux" })
+func (g *GoConfig) IgnoreFieldIf(fieldName string, cond func() bool) *GoConfig {
+	g.tagOverrides = append(g.tagOverrides, tagOverride{
+		FieldName: fieldName,
+		Tag:       "ignore",
+		TagValue:  "true",
+		cond:      cond,
+	})
+	return g
+}
+
 // FieldTag allows for runtime modification of struct field tags. Field names are dot "." separated
 // values when referring to struct fields in struct fields.
 //
@@ -824,6 +3248,197 @@ func (g *GoConfig) FieldTag(fieldName, tagName, helpTxt string) *GoConfig {
 	return g
 }
 
+// TypeTag sets the struct field tag "tagName" to "tagValue" on all fields with the value
+// type "valueType" (for example, "time.Time" or "[]string"). Fields that already have the tag
+// (from the struct field or FieldTag) keep their value.
+//
+//	config.TypeTag("time.Time", "fmt", time.RFC822)
+func (g *GoConfig) TypeTag(valueType, tagName, tagValue string) *GoConfig {
+	g.typeTagOverrides = append(g.typeTagOverrides, typeTagOverride{
+		ValueType: valueType,
+		Tag:       tagName,
+		TagValue:  tagValue,
+	})
+	return g
+}
+
+// WithNonExiting makes Load return an error instead of exiting the application for the
+// standard flag actions so go-config can be embedded in a larger CLI:
+// - ErrHelpRequested // --help,-h (the help menu is still written)
+// - ErrVersionRequested // --version,-v
+// - ErrTemplateGenerated // --gen,-g and --gen-resolved
+// - ErrValuesShown // --show
+// - ErrConfigChecked // --check with a valid config (an invalid config returns the error)
+//
+// Invalid flags also return an error instead of exiting (see flg.Options.ContinueOnError).
+// ExitCode returns 0 for the standard flag action errors.
+func (g *GoConfig) WithNonExiting() *GoConfig {
+	g.nonExiting = true
+	return g
+}
+
+// WithSelfReferences enables expanding "${name}" references in string field values to
+// the value of another field after all loaders run. For example, 'log_dir = "${data_dir}/logs"'.
+//
+// "name" is the full (dot "." separated) field name matched ignoring case, "_" and "-" so
+// the field name (i.e. "DB.DataDir") or the config file name (i.e. "db.data_dir") can be
+// used. Referenced string fields are expanded first. Unknown fields and reference cycles
+// return an error.
+//
+// References are expanded before secret resolvers and exec sources (see WithSecretResolver
+// and EnableExecSources) run. A reference to a secret or 'exec' field expands to the loaded
+// (unresolved) value and a secret reference can itself contain references.
+func (g *GoConfig) WithSelfReferences() *GoConfig {
+	g.selfRefs = true
+	return g
+}
+
+// WithTemplateMerge makes config template generation (--gen) additive. When the config
+// file path (--config,-c) has an extension of the generated format and the file exists, the
+// existing file is written as is (preserving comments, anchors, etc.) followed by only the
+// values missing from it.
+//
+// Only supported for formats with an Unloader implementing load.MergeUnloader (such as yaml).
+// Other formats generate the full template.
+func (g *GoConfig) WithTemplateMerge() *GoConfig {
+	g.templateMerge = true
+	return g
+}
+
+// WithFileRootKey loads the config file values from the section under "key" instead of
+// the whole file so one config file can hold the configs of many services. For example,
+// with the following yaml the "api" section values are loaded:
+//
+//	api:
+//	  host: localhost
+//	worker:
+//	  host: queue.internal
+//
+//	config.WithFileRootKey("api").Load(&appCfg)
+//
+// Nested sections are dot "." separated (i.e. "services.api"). The root key section is
+// selected before the profile section (see WithProfile). Load returns an error if the
+// config file does not have the section. Generated templates do not include the root key.
+//
+// Supported by the toml, yaml and json loaders (see load.SectionLoader).
+func (g *GoConfig) WithFileRootKey(key string) *GoConfig {
+	g.fileRootKey = key
+	return g
+}
+
+// WithLenientFileParsing removes a leading UTF-8 BOM and normalizes Windows ("\r\n") line
+// endings to "\n" in config files before they are parsed. Useful for config files edited on
+// Windows that are otherwise valid but fail to parse (the toml and json parsers reject a BOM).
+// Applies to all config files (including --config, LoadFS, LoadReader and config URLs).
+func (g *GoConfig) WithLenientFileParsing() *GoConfig {
+	g.lenientFiles = true
+	return g
+}
+
+// WithInlineConfigEnv reads the whole config from the env variable "name" when it's set.
+// Useful for platforms that inject the config as a single env variable. For example:
+//
+//	config.WithInlineConfigEnv("APP_CONFIG", "json").Load(&appCfg) // APP_CONFIG='{"db":{"host":"db.internal"}}'
+//
+// The env value is read by the file loader registered for the "format" file extension
+// (i.e. "json" or "yaml") the same as a config file. It's loaded before all the loaders
+// in the "With" list so individual env, file and flag values take precedence. The file
+// root key and profile (see WithFileRootKey and WithProfile) are applied.
+//
+// Load returns a LoaderNotFoundErr when the env variable is set and no file loader is
+// registered for "format".
+func (g *GoConfig) WithInlineConfigEnv(name, format string) *GoConfig {
+	g.inlineCfgEnv = name
+	g.inlineCfgFormat = format
+	return g
+}
+
+// WithProfile loads the config file values from a top level section (profile) of the
+// config file instead of the whole file. For example, with the following yaml
+// the "prod" section values are loaded when the APP_ENV env variable is "prod":
+//
+//	dev:
+//	  host: localhost
+//	prod:
+//	  host: db.internal
+//
+//	config.WithProfile("APP_ENV").Load(&appCfg)
+//
+// "envVarOrValue" is the name of the env variable the profile is read from when loading
+// the config file. If the env variable is not set then "envVarOrValue" is used as the
+// profile name. Load returns an error if the config file does not have the profile section.
+//
+// Supported by the toml, yaml and json loaders (see load.SectionLoader).
+func (g *GoConfig) WithProfile(envVarOrValue string) *GoConfig {
+	g.profile = envVarOrValue
+	return g
+}
+
+// WarnOutput sets where warnings are written. Defaults to os.Stderr.
+//
+// A warning is written when a field with the 'deprecated:"message"' struct tag
+// is loaded with a value. For example:
+//
+//	OldHost string `deprecated:"use --host instead"`
+func (g *GoConfig) WarnOutput(w io.Writer) *GoConfig {
+	g.warnOut = w
+	return g
+}
+
+// Override sets field values from a comma separated list of "key=value" pairs after all
+// loaders run. Keys are the full (dot "." separated) field names and are matched case-insensitively.
+// For example:
+//
+//	config.Override("db.host=localhost,db.port=5432").Load(&appCfg)
+//
+// A convenient way to set deep fields from a single env or flag value. Slice values are split
+// with the 'sep' tag separator. A "," only separates pairs when followed by a "key=" so
+// "hosts=a,b,port=80" sets "Hosts" to [a b]. Overrides are applied in the order provided and
+// an error is returned by Load for unknown keys or invalid values.
+//
+// Numeric key segments are slice indices so a single slice item can be set. For example,
+// "servers.0.host=x" sets the host of the first "Servers" item. An error is returned for
+// an out of range index or indexing a non-slice field.
+func (g *GoConfig) Override(s string) *GoConfig {
+	g.overrides = append(g.overrides, s)
+	return g
+}
+
+// FieldTransform registers a func to normalize a field value after all values are loaded
+// (for example, to lowercase an email or trim a path). "fn" is called with the field value
+// string and the returned value is set on the field. Field names are dot "." separated
+// values when referring to struct fields in struct fields.
+//
+// Slice items are joined (and split again) with the 'sep' tag separator ("," by default)
+// and time.Time values are formatted with the 'fmt' tag format.
+//
+// Field names are validated when "Load" is called. Load returns an error for struct (and
+// slice of struct) fields. Transforms run in the order registered
+// after secret resolvers and before validation.
+func (g *GoConfig) FieldTransform(fieldName string, fn func(string) (string, error)) *GoConfig {
+	g.fieldTransforms = append(g.fieldTransforms, fieldTransform{fieldName: fieldName, fn: fn})
+	return g
+}
+
+// FieldValidate registers a func to validate a single field value after all values are loaded.
+// "fn" is called with the typed field value (for example, an int for an int field) and a returned
+// error is a validation failure annotated with the field name. Field names are dot "." separated
+// values when referring to struct fields in struct fields.
+//
+//	config.FieldValidate("DB.Port", func(v interface{}) error {
+//	    if v.(int) < 1024 {
+//	        return errors.New("must not be a privileged port")
+//	    }
+//	    return nil
+//	})
+//
+// Field names are validated when "Load" is called. Validators run in the order registered
+// with the other post load validation and all failures are returned as a *ValidationErr.
+func (g *GoConfig) FieldValidate(fieldName string, fn func(value interface{}) error) *GoConfig {
+	g.fieldValidators = append(g.fieldValidators, fieldValidator{fieldName: fieldName, fn: fn})
+	return g
+}
+
 // SetConfigPath can be used to set the config path in a manner other than through the
 // standard "--config,-c" standard flag.
 //
@@ -838,6 +3453,196 @@ func (g *GoConfig) SetConfigPath(pth string) *GoConfig {
 	return g
 }
 
+// RequireConfigFile will cause Load to return a ConfigFileErr when no config file path is
+// provided (see SetConfigPath and ConfigPathEnv for how the path is resolved) instead of
+// loading values from the non-file loaders only.
+func (g *GoConfig) RequireConfigFile() *GoConfig {
+	g.requireCfgFile = true
+	return g
+}
+
+// ConfigFileLoaded returns true if the most recent load read in a config
+// file (or config bytes when using LoadReader).
+func (g *GoConfig) ConfigFileLoaded() bool {
+	return g.cfgFileLoaded
+}
+
+// Sources returns the name of the loader (such as "env", "flag" or "yaml") that
+// set the final value of each field of the most recent load by full field name
+// (dot "." separated). Fields set with Override are "override", fields set with Set are
+// "set" and fields not set by any loader are "default".
+//
+// A loader only counts as the source when it changed the field value. For example, a
+// field set by env to its default value is "default".
+func (g *GoConfig) Sources() map[string]string {
+	sources := make(map[string]string)
+	for _, nGrp := range g.nGrps {
+		for _, n := range nGrp.List() {
+			if n.IsStruct() && !n.IsTime() {
+				continue
+			}
+
+			src := n.GetMeta(sourceMeta)
+			if src == "" {
+				src = defaultSource
+			}
+			sources[n.FullName()] = src
+		}
+	}
+
+	return sources
+}
+
+// Get returns the current value of the field "fullName" (dot "." separated) of the
+// most recent load. For example, Get("DB.Port") returns the int port value. Numeric
+// name segments are slice indices. For example, Get("Servers.0.Host").
+//
+// The first matching field is used when more than one app config has the field.
+func (g *GoConfig) Get(fullName string) (interface{}, error) {
+	nodes, err := findPathNodes(fullName, false, g.nGrps)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no field found by name '%v'", fullName)
+	}
+
+	return nodes[0].FieldValue.Interface(), nil
+}
+
+// Set sets the field "fullName" (dot "." separated) of the most recent load from the
+// string "value" as it would be set by an override (see Override). The field source
+// (see Sources) is "set".
+//
+// All app configs with the field are set.
+func (g *GoConfig) Set(fullName, value string) error {
+	nodes, err := findPathNodes(fullName, false, g.nGrps)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("no field found by name '%v'", fullName)
+	}
+
+	for _, n := range nodes {
+		if err := setNodeValue(n, value); err != nil {
+			return err
+		}
+		n.SetMeta(sourceMeta, setSource)
+	}
+
+	return nil
+}
+
+// Drift compares the values of the most recent load with the config file at "pth" and
+// returns an entry for each field with a different file value. The file is loaded (with
+// the file loader registered for the "pth" extension) into a copy of each loaded app config
+// so fields not in the file are not reported.
+//
+// The file root key and profile (see WithFileRootKey and WithProfile) are applied. Values
+// are reported as-is so fields with the 'show:"false"' tag are not redacted.
+func (g *GoConfig) Drift(pth string) ([]DriftEntry, error) {
+	if len(g.nGrps) == 0 {
+		return nil, fmt.Errorf("nothing loaded to compare")
+	}
+
+	ext := strings.TrimPrefix(path.Ext(pth), ".")
+	if ext == "" {
+		return nil, &ConfigExtNotFoundErr{path: pth}
+	}
+	var lu *LoadUnloader
+	for _, regLU := range g.lus {
+		if itemIn(ext, regLU.FileExts) != "" {
+			lu = regLU
+		}
+	}
+	if lu == nil {
+		return nil, &LoaderNotFoundErr{lExt: ext}
+	}
+
+	b, err := ioutil.ReadFile(pth)
+	if err != nil {
+		return nil, &ConfigFileErr{path: pth, err: err}
+	}
+
+	cfgs := make([]interface{}, 0, len(g.nGrps))
+	for _, nGrp := range g.nGrps {
+		cfgs = append(cfgs, util.DeepCopy(nGrp.StructPtr()))
+	}
+	fileNGrps := node.MakeAllNodes(node.Options{
+		NoFollow:  []string{"time.Time"},
+		NoInitNil: g.noInitNil,
+	}, cfgs...)
+
+	if err := g.applyTagOverrides(fileNGrps); err != nil {
+		return nil, err
+	}
+	if err := g.loadWith(lu.Name, lu.Loader, g.fileBytes(b), fileNGrps, fileNGrps); err != nil {
+		return nil, err
+	}
+
+	entries := make([]DriftEntry, 0)
+	for i, nGrp := range g.nGrps {
+		fileNodes := fileNGrps[i].Map()
+		for _, n := range nGrp.List() {
+			if n.IsStruct() && !n.IsTime() {
+				continue
+			}
+
+			fn, ok := fileNodes[n.FullName()]
+			if !ok {
+				continue
+			}
+
+			loaded, file := n.FieldValue.Interface(), fn.FieldValue.Interface()
+			if !reflect.DeepEqual(loaded, file) {
+				entries = append(entries, DriftEntry{FieldName: n.FullName(), Loaded: loaded, File: file})
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// DriftEntry is a field with a loaded value different from the config file value (see Drift).
+type DriftEntry struct {
+	FieldName string      // Full field name (dot "." separated).
+	Loaded    interface{} // Loaded field value.
+	File      interface{} // Config file field value.
+}
+
+// configPath returns the config file path of the current load. The --config flag value
+// (when "flagSet" is true) takes precedence over the ConfigPathEnv env variable value
+// which takes precedence over the SetConfigPath value "defPath".
+//
+// The resolved path is not stored so a later load without the flag or env
+// variable uses the SetConfigPath value again.
+func (g *GoConfig) configPath(defPath string, flagSet bool) string {
+	if flagSet {
+		return g.stdFlgs.ConfigPath
+	}
+
+	if g.cfgPathEnv != "" {
+		if pth := os.Getenv(g.cfgPathEnv); pth != "" {
+			return pth
+		}
+	}
+
+	return defPath
+}
+
+// ConfigPathEnv sets the name of an env variable to read the config path from
+// when the --config,-c flag is not provided.
+//
+// Precedence is:
+// - --config,-c flag value
+// - ConfigPathEnv env variable value (when not empty)
+// - SetConfigPath value
+func (g *GoConfig) ConfigPathEnv(name string) *GoConfig {
+	g.cfgPathEnv = name
+	return g
+}
+
 // allExts returns a unique list of all included file extensions excluding "flag".
 func (g *GoConfig) allExts() []string {
 	exts := make([]string, 0)
@@ -892,6 +3697,12 @@ func itemIn(name string, withList []string) string {
 	return ""
 }
 
+// SecretResolver resolves a secret reference (such as a secret name or path) to
+// the secret value. See *GoConfig.WithSecretResolver.
+type SecretResolver interface {
+	Resolve(ref string) (string, error)
+}
+
 // Validator can be implemented by the user provided config struct.
 // Validate() is called after loading and running tag level validation.
 type Validator interface {
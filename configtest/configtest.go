@@ -0,0 +1,70 @@
+// Package configtest provides helpers for testing how config structs are loaded.
+//
+// For example, to assert a config struct loads from the env:
+//
+//	func TestConfig(t *testing.T) {
+//	    appCfg := &AppConfig{}
+//	    err := configtest.LoadEnv(t, appCfg, map[string]string{"DB_HOST": "localhost"})
+//	    assert.NoError(t, err)
+//	    assert.Equal(t, "localhost", appCfg.DB.Host)
+//	}
+package configtest
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/pcelvng/go-config/load/env"
+	flg "github.com/pcelvng/go-config/load/flag"
+	"github.com/pcelvng/go-config/util/node"
+)
+
+// LoadEnv loads the env values "envVals" (env name to value) into the config struct
+// pointer "appCfg" with the env loader and returns any error.
+//
+// The env values are read as a fixed environment (see env.EnvLoader.WithEnviron) so
+// the process environment is neither read nor modified and tests can run in parallel.
+func LoadEnv(t testing.TB, appCfg interface{}, envVals map[string]string) error {
+	t.Helper()
+
+	return env.NewEnvLoader().WithEnviron(environ(envVals)).Load(nil, makeNodes(appCfg))
+}
+
+// LoadEnvPrefix behaves like LoadEnv only the env names are generated with the global
+// "prefix" (as set with config.NewWithPrefix). For example, "MYAPP_DB_HOST".
+func LoadEnvPrefix(t testing.TB, appCfg interface{}, prefix string, envVals map[string]string) error {
+	t.Helper()
+
+	return env.NewEnvLoader().WithPrefix(prefix).WithEnviron(environ(envVals)).Load(nil, makeNodes(appCfg))
+}
+
+// LoadFlags parses the command line arguments "args" (without the program name) into
+// the config struct pointer "appCfg" with the flag loader and returns any error.
+// Invalid flags return an error instead of exiting the process.
+func LoadFlags(t testing.TB, appCfg interface{}, args []string) error {
+	t.Helper()
+
+	if args == nil {
+		args = []string{}
+	}
+
+	return flg.NewLoader(flg.Options{Args: args, ContinueOnError: true}).Load(nil, makeNodes(appCfg))
+}
+
+// makeNodes generates the node groups of "appCfg" the same way go-config does.
+func makeNodes(appCfg interface{}) []*node.Nodes {
+	return node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, appCfg)
+}
+
+// environ returns "envVals" as sorted "KEY=value" entries.
+func environ(envVals map[string]string) []string {
+	entries := make([]string, 0, len(envVals))
+	for k, v := range envVals {
+		entries = append(entries, k+"="+v)
+	}
+	sort.Strings(entries)
+
+	return entries
+}
@@ -0,0 +1,64 @@
+package configtest
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConfig struct {
+	Name    string
+	Timeout time.Duration
+	Tags    []string
+	DB      struct {
+		Host string
+		Port int
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	os.Setenv("NAME", "from-os")
+	defer os.Unsetenv("NAME")
+
+	c := &testConfig{}
+	err := LoadEnv(t, c, map[string]string{
+		"TIMEOUT": "1m",
+		"TAGS":    "a,b",
+		"DB_HOST": "localhost",
+		"DB_PORT": "5432",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "", c.Name) // process env is not read.
+	assert.Equal(t, time.Minute, c.Timeout)
+	assert.Equal(t, []string{"a", "b"}, c.Tags)
+	assert.Equal(t, "localhost", c.DB.Host)
+	assert.Equal(t, 5432, c.DB.Port)
+	assert.Equal(t, "from-os", os.Getenv("NAME"))
+
+	err = LoadEnv(t, c, map[string]string{"DB_PORT": "abc"})
+	assert.Error(t, err)
+}
+
+func TestLoadEnvPrefix(t *testing.T) {
+	c := &testConfig{}
+	err := LoadEnvPrefix(t, c, "myapp", map[string]string{
+		"MYAPP_NAME": "a",
+		"DB_HOST":    "b",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "a", c.Name)
+	assert.Equal(t, "", c.DB.Host)
+}
+
+func TestLoadFlags(t *testing.T) {
+	c := &testConfig{}
+	err := LoadFlags(t, c, []string{"--name=a", "--db-port", "80"})
+	assert.NoError(t, err)
+	assert.Equal(t, "a", c.Name)
+	assert.Equal(t, 80, c.DB.Port)
+
+	err = LoadFlags(t, c, []string{"--unknown"})
+	assert.Error(t, err)
+}
@@ -7,6 +7,7 @@ require (
 	github.com/iancoleman/strcase v0.2.0
 	github.com/jbsmith7741/trial v0.3.1
 	github.com/stretchr/testify v1.7.0
+	golang.org/x/term v0.20.0
 	gopkg.in/yaml.v2 v2.4.0
 )
 
@@ -14,5 +15,6 @@ require (
 	github.com/davecgh/go-spew v1.1.0 // indirect
 	github.com/google/go-cmp v0.4.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.20.0 // indirect
 	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
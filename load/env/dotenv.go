@@ -0,0 +1,114 @@
+package env
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseDotEnv parses dotenv formatted bytes into a map of env names to values.
+//
+// Supported syntax:
+//   - blank lines and lines starting with "#" are skipped.
+//   - an optional "export " prefix (as written by the EnvUnloader).
+//   - unquoted values with optional trailing " # comment".
+//   - single quoted values which are read literally.
+//   - double quoted values which may span multiple lines and support the
+//     escape sequences \n, \r, \t, \", \\, \$ and \`.
+func parseDotEnv(b []byte) (map[string]string, error) {
+	vals := make(map[string]string)
+	lines := strings.Split(strings.ReplaceAll(string(b), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		eq := strings.Index(line, "=")
+		if eq < 1 {
+			return nil, fmt.Errorf("dotenv line %d: expected NAME=VALUE", lineNum)
+		}
+		name := strings.TrimSpace(line[:eq])
+		raw := strings.TrimSpace(line[eq+1:])
+
+		switch {
+		case strings.HasPrefix(raw, `"`):
+			// Join continuation lines until the closing quote.
+			val, ok := unquoteDouble(raw[1:])
+			for !ok && i+1 < len(lines) {
+				i++
+				raw += "\n" + lines[i]
+				val, ok = unquoteDouble(raw[1:])
+			}
+			if !ok {
+				return nil, fmt.Errorf("dotenv line %d: unterminated quoted value for '%v'", lineNum, name)
+			}
+			vals[name] = val
+		case strings.HasPrefix(raw, "'"):
+			end := strings.Index(raw[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("dotenv line %d: unterminated quoted value for '%v'", lineNum, name)
+			}
+			vals[name] = raw[1 : end+1]
+		default:
+			if idx := strings.Index(raw, " #"); idx >= 0 {
+				raw = raw[:idx]
+			}
+			vals[name] = strings.TrimSpace(raw)
+		}
+	}
+
+	return vals, nil
+}
+
+// unquoteDouble reads the double quoted value "s" (without the opening quote)
+// up to the closing quote and unescapes it. Returns false if there is no
+// closing quote.
+func unquoteDouble(s string) (string, bool) {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return sb.String(), true
+		case '\\':
+			if i+1 == len(s) {
+				sb.WriteByte(s[i])
+				continue
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case '"', '\\', '$', '`':
+				sb.WriteByte(s[i])
+			default:
+				sb.WriteByte('\\')
+				sb.WriteByte(s[i])
+			}
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+
+	return "", false
+}
+
+// quoteDouble double quotes "s" so that it's read back the same by
+// both "sh" and parseDotEnv. Newlines are kept as is so multi-line
+// values (such as PEM blocks) remain readable.
+func quoteDouble(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "`", "\\`")
+	return `"` + r.Replace(s) + `"`
+}
+
+// needsQuote reports if the env value "s" must be quoted to be read
+// back correctly.
+func needsQuote(s string) bool {
+	return strings.ContainsAny(s, "\n\r\t \"'\\$`#")
+}
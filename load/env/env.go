@@ -1,6 +1,7 @@
 package env
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/pcelvng/go-config/util"
@@ -13,25 +14,81 @@ var (
 	fmtTag    = "fmt"
 	helpTag   = "help" // Only used for encoding.
 	ignoreTag = "ignore"
-	sepTag    = "sep" // separator for slice values.
+	noEnvTag  = "noenv" // excludes the field from the env loader only.
+	sepTag    = "sep"   // separator for slice values.
 
-	defaultSep = "," // default separator for encoding/decoding slice values.
+	envMeta = "env" // Node meta key of the env name (see EnvLoader.RecordNames).
+
+	fallbackSep = "|" // separator of the 'env' tag fallback names (i.e. 'env:"NEW_NAME|OLD_NAME"').
+
+	defaultSep     = "," // default separator for encoding/decoding slice values.
+	defaultNameSep = "_" // default separator between env name heritage levels.
+
+	caseUpper = "upper" // SCREAMING_SNAKE_CASE field names (default).
+	caseLower = "lower" // lower_snake_case names.
+	caseAsIs  = "asis"  // exact field names.
+
+	collisionError  = "error"               // two fields with the same env name return an error (default).
+	collisionPrefix = "prefix-disambiguate" // colliding field names include all parent names.
+	collisionFirst  = "first-wins"          // the later field is not loaded.
 )
 
+// checkNameCase returns an error if 'nameCase' is not a supported env name case.
+func checkNameCase(nameCase string) error {
+	switch nameCase {
+	case caseUpper, caseLower, caseAsIs:
+		return nil
+	}
+
+	return fmt.Errorf("unknown env name case '%v'", nameCase)
+}
+
+// checkCollisionStrategy returns an error if 'strategy' is not a supported name collision strategy.
+func checkCollisionStrategy(strategy string) error {
+	switch strategy {
+	case "", collisionError, collisionPrefix, collisionFirst:
+		return nil
+	}
+
+	return fmt.Errorf("unknown env name collision strategy '%v'", strategy)
+}
+
 // genFullName generates the full env name including the prefix.
-func genFullName(prefix string, n *node.Node, heritage []*node.Node) (fullName string) {
-	return genPrefix(prefix, append(heritage, n))
+//
+// 'nameSep' is the separator placed between the prefix and each heritage level.
+//
+// 'nameCase' is the env name case (see EnvLoader.WithCase).
+func genFullName(prefix, nameSep, nameCase string, n *node.Node, heritage []*node.Node) (fullName string) {
+	return genPrefix(prefix, nameSep, nameCase, append(heritage, n))
 }
 
 // genPrefix generates the env name prefix.
 //
 // 'heritage' is expected to be ordered from most to least distant relative.
-func genPrefix(globalPrefix string, heritage []*node.Node) (prefix string) {
+func genPrefix(globalPrefix, nameSep, nameCase string, heritage []*node.Node) (prefix string) {
+	return genName(globalPrefix, nameSep, nameCase, heritage, false)
+}
+
+// genQualifiedName generates the full env name including the prefix and the names
+// of all parents. Parents with the 'omitprefix' env tag use the field name.
+func genQualifiedName(prefix, nameSep, nameCase string, n *node.Node, heritage []*node.Node) string {
+	return genName(prefix, nameSep, nameCase, append(heritage, n), true)
+}
+
+// genName joins the global prefix and the env names of 'heritage'. When 'qualified'
+// is true nodes with the 'omitprefix' env tag use the field name instead of being skipped.
+func genName(globalPrefix, nameSep, nameCase string, heritage []*node.Node, qualified bool) (prefix string) {
 	if globalPrefix != "" {
 		prefix = globalPrefix
+		if nameCase != caseAsIs {
+			prefix = util.ToScreamingSnake(globalPrefix)
+		}
 	}
 	for _, hn := range heritage {
-		envName := nodeEnvName(hn)
+		envName := nodeEnvName(hn, nameCase)
+		if envName == "" && qualified {
+			envName = fieldEnvName(hn, nameCase)
+		}
 		if envName == "" {
 			continue
 		}
@@ -39,27 +96,113 @@ func genPrefix(globalPrefix string, heritage []*node.Node) (prefix string) {
 		if prefix == "" {
 			prefix += envName
 		} else {
-			prefix += "_" + envName
+			prefix += nameSep + envName
 		}
 	}
 
+	if nameCase == caseLower {
+		return strings.ToLower(prefix)
+	}
+
 	return prefix
 }
 
+// genNames generates the full env name of each loadable node in 'nGrps' resolving
+// names generated by more than one field with the collision 'strategy':
+// - "error" // an error naming both fields is returned (default).
+// - "prefix-disambiguate" // all the fields use their qualified name (see genQualifiedName).
+// - "first-wins" // the later fields are left out.
+//
+// An error is returned when names are still generated by more than one field.
+func genNames(prefix, nameSep, nameCase, strategy string, nGrps []*node.Nodes) (map[*node.Node]string, error) {
+	type field struct {
+		n        *node.Node
+		heritage []*node.Node
+		name     string
+	}
+
+	fields := make([]field, 0)
+	counts := make(map[string]int)
+	for _, nGrp := range nGrps {
+		for _, n := range nGrp.List() {
+			heritage := node.Parents(n, nGrp.Map())
+			if isAnyIgnored(append(heritage, n)) || (n.IsStruct() && !n.IsTime()) || n.IsFileOnly() {
+				continue
+			}
+
+			name := genFullName(prefix, nameSep, nameCase, n, heritage)
+			fields = append(fields, field{n: n, heritage: heritage, name: name})
+			counts[name]++
+		}
+	}
+
+	names := make(map[*node.Node]string)
+	used := make(map[string]string) // env name -> field full name
+	for _, f := range fields {
+		name := f.name
+		if strategy == collisionPrefix && counts[name] > 1 {
+			name = genQualifiedName(prefix, nameSep, nameCase, f.n, f.heritage)
+		}
+
+		if fullName, ok := used[name]; ok {
+			if strategy == collisionFirst {
+				continue
+			}
+			return nil, fmt.Errorf("env name '%v' generated by both '%v' and '%v'", name, fullName, f.n.FullName())
+		}
+		used[name] = f.n.FullName()
+		names[f.n] = name
+	}
+
+	return names, nil
+}
+
+// genFallbackNames generates the full fallback env names (including the prefix) of the
+// 'env' tag fallback names. For example, 'env:"NEW_NAME|OLD_NAME"' generates "OLD_NAME"
+// with the same prefix as "NEW_NAME".
+func genFallbackNames(prefix, nameSep, nameCase string, n *node.Node, heritage []*node.Node) []string {
+	fallbacks := strings.Split(getEnvTag(n), fallbackSep)[1:]
+	parent := genPrefix(prefix, nameSep, nameCase, heritage)
+
+	names := make([]string, 0, len(fallbacks))
+	for _, fallback := range fallbacks {
+		name := strings.TrimSpace(fallback)
+		if parent != "" {
+			name = parent + nameSep + name
+		}
+		if nameCase == caseLower {
+			name = strings.ToLower(name)
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
+
 // nodeEnvName generates the env name of the node. Does
-// not include the prefix.
-func nodeEnvName(n *node.Node) string {
-	ev := getEnvTag(n)
+// not include the prefix or any fallback names.
+//
+// Field names are converted to SCREAMING_SNAKE_CASE unless 'nameCase' is "asis".
+func nodeEnvName(n *node.Node, nameCase string) string {
+	ev := strings.Split(getEnvTag(n), fallbackSep)[0]
 	switch ev {
 	case "omitprefix":
 		return ""
 	case "":
-		return util.ToScreamingSnake(n.FieldName())
+		return fieldEnvName(n, nameCase)
 	default:
 		return ev
 	}
 }
 
+// fieldEnvName generates the env name of the node from the field name.
+func fieldEnvName(n *node.Node, nameCase string) string {
+	if nameCase == caseAsIs {
+		return n.FieldName()
+	}
+	return util.ToScreamingSnake(n.FieldName())
+}
+
 // isAnyIgnored checks if any members of 'nodes' is ignored.
 // if so, then returns true.
 func isAnyIgnored(nodes []*node.Node) bool {
@@ -79,10 +222,12 @@ func isAnyIgnored(nodes []*node.Node) bool {
 // - `ignore:"true"`
 // - `config:"ignore"`
 // - `env:"-"`
+// - `noenv:"true"`
 func isIgnored(n *node.Node) bool {
 	// "ignore" tag or "config" tag has ("ignore" value)
 	if n.GetBoolTag(ignoreTag) ||
 		n.GetTag(configTag) == "ignore" ||
+		n.GetBoolTag(noEnvTag) ||
 		getEnvTag(n) == "-" {
 		return true
 	}
@@ -93,7 +238,7 @@ func isIgnored(n *node.Node) bool {
 // getEnvTag returns the 'env' tag value. It
 // knows to exclude the supported ',string' suffix option if present.
 func getEnvTag(n *node.Node) string {
-	return strings.TrimRight(n.GetTag(envTag), ",string")
+	return strings.TrimSuffix(n.GetTag(envTag), ",string")
 }
 
 // isEnvString returns true when the env tag value has the suffix ",string".
@@ -105,7 +250,7 @@ func isEnvString(n *node.Node) bool {
 // the struct field node if one is provided. If a separator is not
 // provided then the default separator is returned.
 func getSep(n *node.Node) string {
-	sep := n.GetTag(sepTag)
+	sep := n.SliceSep()
 	if sep == "" {
 		sep = defaultSep
 	}
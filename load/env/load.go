@@ -2,42 +2,389 @@ package env
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
-	"github.com/pcelvng/go-config/util"
 	"github.com/pcelvng/go-config/util/node"
 )
 
 func NewEnvLoader() *EnvLoader {
-	return &EnvLoader{}
+	return &EnvLoader{nameSep: defaultNameSep, nameCase: caseUpper}
 }
 
 func (l *EnvLoader) WithPrefix(prefix string) *EnvLoader {
-	l.prefix = util.ToScreamingSnake(prefix)
+	l.prefix = prefix
+	l.cache = nil
+	return l
+}
+
+// WithSeparator sets the separator placed between the prefix and each
+// struct level of the env name. For example, "." results in "MYAPP.DB.HOST".
+// The default separator is "_".
+func (l *EnvLoader) WithSeparator(sep string) *EnvLoader {
+	l.nameSep = sep
+	l.cache = nil
+	return l
+}
+
+// WithCase sets the case of generated env names. Options are:
+// - "upper" // SCREAMING_SNAKE_CASE field names and prefix (default). For example, "MYAPP_DB_HOST".
+// - "lower" // the "upper" name lowercased (including env tag names). For example, "myapp_db_host".
+// - "asis" // exact field names, env tag names and prefix. For example, "myApp_DB_Host".
+//
+// Load returns an error for an unknown case.
+func (l *EnvLoader) WithCase(nameCase string) *EnvLoader {
+	l.nameCase = nameCase
+	l.cache = nil
+	return l
+}
+
+// WithCollisionStrategy sets how env names generated by more than one field are
+// resolved. Options are:
+// - "error" // Load returns an error naming both fields (default).
+// - "prefix-disambiguate" // the colliding field names include the names of all parents (including
+// 'omitprefix' parents). For example, "DB_HOST" instead of "HOST".
+// - "first-wins" // the first field keeps the name and the later field is not loaded.
+//
+// Load returns an error for an unknown strategy or when a disambiguated name is also taken.
+func (l *EnvLoader) WithCollisionStrategy(strategy string) *EnvLoader {
+	l.collision = strategy
+	l.cache = nil
+	return l
+}
+
+// WithWarnOutput sets where warnings (such as a fallback env name being used)
+// are written. Defaults to os.Stderr.
+func (l *EnvLoader) WithWarnOutput(w io.Writer) *EnvLoader {
+	l.warnOut = w
+	return l
+}
+
+// WithSliceSplitter sets how slice env values are split into items. "sep" is the field
+// separator ("," by default) and "isString" is true for fields with the ",string" env
+// tag option. The default splitting trims spaces and surrounding "[]" (and quotes when
+// "isString" is true).
+func (l *EnvLoader) WithSliceSplitter(fn func(raw, sep string, isString bool) []string) *EnvLoader {
+	l.split = fn
+	return l
+}
+
+// WithEnviron sets the environment read by Load as "KEY=value" entries (the same
+// form as os.Environ) instead of the process environment. Useful for tests
+// to load a fixed environment without os.Setenv.
+func (l *EnvLoader) WithEnviron(environ []string) *EnvLoader {
+	l.environ = environ
+	return l
+}
+
+// WithIndexedSlices enables reading slice fields from numbered env variables where each
+// item has its own variable. For example, "HOSTS_0", "HOSTS_1", etc. Items are read from
+// index 0 until the first index that is not set. The single "HOSTS" value is split as
+// usual when "HOSTS_0" is not set.
+func (l *EnvLoader) WithIndexedSlices() *EnvLoader {
+	l.indexed = true
+	return l
+}
+
+// WithWarnUnused enables writing a warning to "w" for each environment variable
+// starting with the global prefix that is not read by any field. Useful to
+// catch misspelled env names. Nothing is checked without a prefix.
+//
+// Env variables read outside of the loader (such as a config path env) can be
+// excluded with WithUsedNames.
+func (l *EnvLoader) WithWarnUnused(w io.Writer) *EnvLoader {
+	l.unusedOut = w
+	return l
+}
+
+// WithUsedNames sets the full env variable names (for example, "APP_CONFIG") that
+// are read outside of the loader so they are not reported by WithWarnUnused.
+// Empty names are ignored.
+func (l *EnvLoader) WithUsedNames(names ...string) *EnvLoader {
+	l.usedNames = names
 	return l
 }
 
 type EnvLoader struct {
-	prefix string
+	prefix   string
+	nameSep  string
+	nameCase string
+	warnOut  io.Writer
+	split    func(raw, sep string, isString bool) []string
+
+	// collision is the name collision strategy (see WithCollisionStrategy).
+	collision string
+
+	// environ is the environment read by Load. os.Environ is used when nil.
+	environ []string
+
+	// indexed enables reading slices from numbered env variables (see WithIndexedSlices).
+	indexed bool
+
+	// unusedOut is where unused prefixed env variables are reported (see WithWarnUnused).
+	unusedOut io.Writer
+
+	// usedNames are the env variable names read outside of the loader (see WithUsedNames).
+	usedNames []string
+
+	// cache contains the env fields of the most recently loaded node groups.
+	cache *fieldsCache
+}
+
+// fieldsCache contains the env fields generated for "nGrps".
+type fieldsCache struct {
+	nGrps  []*node.Nodes
+	fields []envField
+}
+
+// envField is a loadable field and its full env name.
+type envField struct {
+	name      string
+	fallbacks []string // Full fallback env names in the order checked.
+	n         *node.Node
 }
 
 // Load implements the go-config/load.EnvLoader interface.
 //
-// TODO: load env vars from a file (i.e. from bytes)
-func (l *EnvLoader) Load(_ []byte, nGrps []*node.Nodes) error {
-	for _, nGrp := range nGrps {
-		err := load(l.prefix, nGrp)
+// If "b" is not empty it's read as a dotenv file (see parseDotEnv). Values
+// set in the environment take precedence over dotenv file values.
+//
+// An error is returned if two fields generate the same env name (unless
+// a different collision strategy is set with WithCollisionStrategy).
+//
+// Fields with 'env' tag fallback names (i.e. 'env:"NEW_NAME|OLD_NAME"') use the value
+// of the first name that is set (in the environment or dotenv file). A warning is
+// written when a fallback name is used.
+//
+// Slice fields are read from numbered env variables (i.e. "HOSTS_0") when enabled with
+// WithIndexedSlices.
+//
+// A warning is written for each prefixed env variable not read by any field when
+// enabled with WithWarnUnused.
+//
+// The env fields (and names) are generated once and reused while Load is called with
+// the same node groups. The environment (see WithEnviron) is read once per Load and
+// only the variables starting with the prefix are kept.
+func (l *EnvLoader) Load(b []byte, nGrps []*node.Nodes) error {
+	fields, err := l.fields(nGrps)
+	if err != nil {
+		return err
+	}
+
+	environ := l.environ
+	if environ == nil {
+		environ = os.Environ()
+	}
+	// All generated names start with the (cased) global prefix and separator
+	// so variables such as "APPLE_X" are not read (or reported) for prefix "APP".
+	prefix := genPrefix(l.prefix, l.nameSep, l.nameCase, nil)
+	if prefix != "" {
+		prefix += l.nameSep
+	}
+	envVals := snapshotEnv(environ, prefix)
+
+	fileVals := make(map[string]string)
+	if len(b) > 0 {
+		var err error
+		fileVals, err = parseDotEnv(b)
 		if err != nil {
 			return err
 		}
 	}
 
+	// lookup returns the env value of "name" (falling back to the dotenv file value).
+	used := make(map[string]bool)
+	for _, name := range l.usedNames {
+		used[name] = true
+	}
+	lookup := func(name string) string {
+		envVal, ok := envVals[name]
+		if ok {
+			used[name] = true
+		}
+		if envVal != "" {
+			return envVal
+		}
+		return fileVals[name]
+	}
+
+	// Set field from env value.
+	for _, f := range fields {
+		if l.indexed && f.n.IsSlice() {
+			if items := indexedVals(f.name, l.nameSep, lookup); len(items) > 0 {
+				if err := setSliceItems(f.n, items); err != nil {
+					return fmt.Errorf("%w type=%v field=%s", err, reflect.TypeOf(f.n.FullName()), f.n.FullName())
+				}
+				continue
+			}
+		}
+
+		envVal := lookup(f.name)
+		for _, fallback := range f.fallbacks {
+			if envVal != "" {
+				break
+			}
+
+			if envVal = lookup(fallback); envVal != "" {
+				l.warnFallback(fallback, f.name)
+			}
+		}
+
+		err := setFieldValue(f.n, envVal, l.split)
+		if err != nil {
+			return fmt.Errorf("%w type=%v field=%s", err, reflect.TypeOf(f.n.FullName()), f.n.FullName())
+		}
+	}
+
+	if l.unusedOut != nil && l.prefix != "" {
+		warnUnused(l.unusedOut, envVals, used)
+	}
+
 	return nil
 }
 
-func load(prefix string, nodes *node.Nodes) error {
+// warnUnused writes a warning to "w" for each (sorted) env variable name of "envVals"
+// not in "used".
+func warnUnused(w io.Writer, envVals map[string]string, used map[string]bool) {
+	names := make([]string, 0)
+	for name := range envVals {
+		if !used[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "warning: env '%v' is not used by any field\n", name)
+	}
+}
+
+// indexedVals returns the values of the numbered env variables of "name" starting with
+// "{name}{nameSep}0" until the first index that is not set.
+func indexedVals(name, nameSep string, lookup func(name string) string) []string {
+	vals := make([]string, 0)
+	for i := 0; ; i++ {
+		v := lookup(name + nameSep + strconv.Itoa(i))
+		if v == "" {
+			return vals
+		}
+		vals = append(vals, v)
+	}
+}
+
+// setSliceItems sets (or appends with the 'sep' tag ",append" option) the slice items "vals".
+func setSliceItems(n *node.Node, vals []string) error {
+	if n.IsSliceAppend() {
+		return n.SetSliceAppend(vals)
+	}
+
+	return n.SetSlice(vals)
+}
+
+// snapshotEnv returns the "KEY=value" entries of "environ" as a map of key to value
+// keeping only the keys starting with "prefix".
+func snapshotEnv(environ []string, prefix string) map[string]string {
+	envVals := make(map[string]string)
+	for _, kv := range environ {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" || !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		envVals[k] = v
+	}
+
+	return envVals
+}
+
+// warnFallback writes a warning that the fallback env name "fallback" was used instead of "name".
+func (l *EnvLoader) warnFallback(fallback, name string) {
+	w := l.warnOut
+	if w == nil {
+		w = os.Stderr
+	}
+
+	fmt.Fprintf(w, "warning: env '%v' is deprecated: use '%v'\n", fallback, name)
+}
+
+// RecordNames sets the "env" meta of each loadable node in "nGrps" to the
+// node env name. Useful for documenting the env names elsewhere, such as the
+// flag help menu.
+//
+// An error is returned if two fields generate the same env name.
+func (l *EnvLoader) RecordNames(nGrps []*node.Nodes) error {
+	fields, err := l.fields(nGrps)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		f.n.SetMeta(envMeta, f.name)
+	}
+
+	return nil
+}
+
+// fields returns the env fields of "nGrps" from the cache when "nGrps" are
+// the same node groups as the previous call. Otherwise the fields are generated
+// and cached.
+func (l *EnvLoader) fields(nGrps []*node.Nodes) ([]envField, error) {
+	if l.cache != nil && sameNodes(l.cache.nGrps, nGrps) {
+		return l.cache.fields, nil
+	}
+
+	if err := checkNameCase(l.nameCase); err != nil {
+		return nil, err
+	}
+
+	if err := checkCollisionStrategy(l.collision); err != nil {
+		return nil, err
+	}
+
+	names, err := genNames(l.prefix, l.nameSep, l.nameCase, l.collision, nGrps)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]envField, 0)
+	for _, nGrp := range nGrps {
+		fs, err := genFields(l.prefix, l.nameSep, l.nameCase, names, nGrp)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, fs...)
+	}
+
+	l.cache = &fieldsCache{
+		nGrps:  append([]*node.Nodes{}, nGrps...),
+		fields: fields,
+	}
+
+	return fields, nil
+}
+
+// sameNodes returns true if "a" and "b" contain the same node groups in the same order.
+func sameNodes(a, b []*node.Nodes) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// genFields generates the env fields of the loadable nodes. "names" contains the
+// full env name of each node (see genNames) and nodes without a name are skipped.
+func genFields(prefix, nameSep, nameCase string, names map[*node.Node]string, nodes *node.Nodes) ([]envField, error) {
+	fields := make([]envField, 0, len(nodes.List()))
 	for _, n := range nodes.List() {
 		heritage := node.Parents(n, nodes.Map())
 
@@ -57,26 +404,39 @@ func load(prefix string, nodes *node.Nodes) error {
 			continue
 		}
 
+		// Skip file only fields (such as slices of structs) since they
+		// cannot be expressed as a single value.
+		if n.IsFileOnly() {
+			continue
+		}
+
 		// Validate that "omitprefix" is not used on value fields.
 		if getEnvTag(n) == "omitprefix" {
-			return fmt.Errorf("'omitprefix' cannot be used on non-struct field types")
+			return nil, fmt.Errorf("'omitprefix' cannot be used on non-struct field types")
 		}
 
-		// Set field from env value.
-		err := setFieldValue(n, os.Getenv(genFullName(prefix, n, heritage)))
-		if err != nil {
-			return fmt.Errorf("%w type=%v field=%s", err, reflect.TypeOf(n.FullName()), n.FullName())
+		// Skip fields left out by the collision strategy.
+		name, ok := names[n]
+		if !ok {
+			continue
 		}
+
+		fields = append(fields, envField{
+			name:      name,
+			fallbacks: genFallbackNames(prefix, nameSep, nameCase, n, heritage),
+			n:         n,
+		})
 	}
 
-	return nil
+	return fields, nil
 }
 
 // setFieldValue sets the field value. It takes into account
-// special cases such as time.Time and slices.
+// special cases such as time.Time and slices. Slice values are split with
+// "split" when not nil (see EnvLoader.WithSliceSplitter).
 //
 // If 'envVal' is empty then nothing is set and nil is returned.
-func setFieldValue(n *node.Node, envVal string) error {
+func setFieldValue(n *node.Node, envVal string, split func(raw, sep string, isString bool) []string) error {
 	if envVal == "" {
 		return nil
 	}
@@ -84,8 +444,17 @@ func setFieldValue(n *node.Node, envVal string) error {
 	if n.IsTime() {
 		_, err := n.SetTime(envVal, n.GetTag(fmtTag))
 		return err
+	} else if n.IsSliceJSON() {
+		return n.SetSliceJSON(envVal)
+	}
+
+	if split == nil {
+		split = splitSlice
+	}
+	if n.IsSliceAppend() {
+		return n.SetSliceAppend(split(envVal, getSep(n), isEnvString(n)))
 	} else if n.IsSlice() {
-		return n.SetSlice(splitSlice(envVal, n.GetTag(sepTag), isEnvString(n)))
+		return n.SetSlice(split(envVal, getSep(n), isEnvString(n)))
 	}
 
 	return n.SetFieldValue(envVal)
@@ -96,7 +465,7 @@ func setFieldValue(n *node.Node, envVal string) error {
 // the result is `"1"` is read in as `1` with the quotes stripped away
 // before reading in the value.
 //
-// TODO: allow hook for a custom implementation of this function.
+// See EnvLoader.WithSliceSplitter for a custom implementation.
 func splitSlice(envValue string, sep string, isString bool) []string {
 	if sep == "" {
 		sep = defaultSep
@@ -1,12 +1,18 @@
 package env
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/pcelvng/go-config/util/node"
 
+	"github.com/jbsmith7741/trial"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -252,3 +258,524 @@ func TestDecoder_Unmarshal2(t *testing.T) {
 	assert.EqualError(t, err, "'omitprefix' cannot be used on non-struct field types")
 
 }
+
+func TestEnvLoader_WithSeparator(t *testing.T) {
+	os.Setenv("MYAPP.DB.HOST", "localhost")
+	defer os.Unsetenv("MYAPP.DB.HOST")
+
+	type DB struct {
+		Host string
+	}
+
+	type Options struct {
+		DB DB
+	}
+
+	options := &Options{}
+	err := NewEnvLoader().WithPrefix("myapp").WithSeparator(".").Load([]byte{}, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, options))
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", options.DB.Host)
+}
+
+func TestEnvLoader_DotEnv(t *testing.T) {
+	os.Setenv("OVERRIDE", "from-env")
+	defer os.Unsetenv("OVERRIDE")
+
+	type Options struct {
+		Name     string
+		Key      string
+		Escaped  string
+		Literal  string
+		Override string
+		Port     int
+	}
+
+	fn := func(args ...interface{}) (interface{}, error) {
+		options := &Options{}
+		err := NewEnvLoader().Load([]byte(args[0].(string)), node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, options))
+		return options, err
+	}
+	cases := trial.Cases{
+		"values": {
+			Input: `# comment
+export NAME=app # trailing comment
+KEY="-----BEGIN KEY-----
+abc
+-----END KEY-----"
+ESCAPED="line1\nline2 \"quoted\""
+LITERAL='a\nb'
+OVERRIDE=from-file
+PORT=80
+`,
+			Expected: &Options{
+				Name:     "app",
+				Key:      "-----BEGIN KEY-----\nabc\n-----END KEY-----",
+				Escaped:  "line1\nline2 \"quoted\"",
+				Literal:  `a\nb`,
+				Override: "from-env",
+				Port:     80,
+			},
+		},
+		"unterminated": {
+			Input:       "KEY=\"abc\nPORT=80\n",
+			ExpectedErr: errors.New("dotenv line 1: unterminated quoted value for 'KEY'"),
+		},
+		"missing equals": {
+			Input:       "NAME\n",
+			ExpectedErr: errors.New("dotenv line 1: expected NAME=VALUE"),
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestEnvLoader_DuplicateNames(t *testing.T) {
+	type DB struct {
+		Host string
+	}
+
+	options := &struct {
+		DB   DB `env:"omitprefix"`
+		Host string
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, options)
+
+	err := NewEnvLoader().Load([]byte{}, nGrps)
+	assert.EqualError(t, err, "env name 'HOST' generated by both 'DB.Host' and 'Host'")
+
+	_, err = NewEnvUnloader().Unload(nGrps)
+	assert.EqualError(t, err, "env name 'HOST' generated by both 'DB.Host' and 'Host'")
+
+	// Across config structs.
+	err = NewEnvLoader().Load([]byte{}, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, &struct{ Port int }{}, &struct{ Port int }{}))
+	assert.EqualError(t, err, "env name 'PORT' generated by both 'Port' and 'Port'")
+}
+
+func TestEnvLoader_WithCollisionStrategy(t *testing.T) {
+	type DB struct {
+		Host string
+	}
+
+	type Options struct {
+		DB   DB `env:"omitprefix"`
+		Host string
+	}
+
+	t.Setenv("HOST", "host")
+	t.Setenv("DB_HOST", "db-host")
+	load := func(strategy string) (*Options, error) {
+		o := &Options{}
+		nGrps := node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, o)
+		return o, NewEnvLoader().WithCollisionStrategy(strategy).Load([]byte{}, nGrps)
+	}
+
+	_, err := load("error")
+	assert.EqualError(t, err, "env name 'HOST' generated by both 'DB.Host' and 'Host'")
+
+	o, err := load("prefix-disambiguate")
+	assert.NoError(t, err)
+	assert.Equal(t, &Options{DB: DB{Host: "db-host"}, Host: "host"}, o)
+
+	o, err = load("first-wins")
+	assert.NoError(t, err)
+	assert.Equal(t, &Options{DB: DB{Host: "host"}}, o)
+
+	_, err = load("last-wins")
+	assert.EqualError(t, err, "unknown env name collision strategy 'last-wins'")
+
+	// Unloaded names match loaded names.
+	b, err := NewEnvUnloader().WithCollisionStrategy("prefix-disambiguate").Unload(node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, &Options{}))
+	assert.NoError(t, err)
+	assert.Equal(t, "#!/usr/bin/env sh\n\nexport DB_HOST=\nexport HOST=\n", string(b))
+
+	// Fields without parents cannot be disambiguated.
+	err = NewEnvLoader().WithCollisionStrategy("prefix-disambiguate").Load([]byte{}, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, &struct{ Port int }{}, &struct{ Port int }{}))
+	assert.EqualError(t, err, "env name 'PORT' generated by both 'Port' and 'Port'")
+}
+
+func TestEnvLoader_WithCase(t *testing.T) {
+	type DB struct {
+		MaxConn  int
+		Username string `env:"UN"`
+	}
+
+	type Options struct {
+		LogLevel string
+		DB       DB
+	}
+
+	fn := func(args ...interface{}) (interface{}, error) {
+		envs := map[string]string{
+			"MY_APP_LOG_LEVEL":   "upper",
+			"MY_APP_DB_MAX_CONN": "1",
+			"MY_APP_DB_UN":       "upper",
+			"my_app_log_level":   "lower",
+			"my_app_db_max_conn": "2",
+			"my_app_db_un":       "lower",
+			"myApp_LogLevel":     "asis",
+			"myApp_DB_MaxConn":   "3",
+			"myApp_DB_UN":        "asis",
+		}
+		for k, v := range envs {
+			os.Setenv(k, v)
+		}
+		defer func() {
+			for k := range envs {
+				os.Unsetenv(k)
+			}
+		}()
+
+		o := &Options{}
+		err := NewEnvLoader().WithPrefix("myApp").WithCase(args[0].(string)).Load(nil, node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, o))
+		return o, err
+	}
+	cases := trial.Cases{
+		"upper": {
+			Input:    "upper",
+			Expected: &Options{LogLevel: "upper", DB: DB{MaxConn: 1, Username: "upper"}},
+		},
+		"lower": {
+			Input:    "lower",
+			Expected: &Options{LogLevel: "lower", DB: DB{MaxConn: 2, Username: "lower"}},
+		},
+		"asis": {
+			Input:    "asis",
+			Expected: &Options{LogLevel: "asis", DB: DB{MaxConn: 3, Username: "asis"}},
+		},
+		"unknown": {
+			Input:       "camel",
+			ExpectedErr: errors.New("unknown env name case 'camel'"),
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestEnvLoader_SliceAppend(t *testing.T) {
+	os.Setenv("HOSTS", "b,c")
+	os.Setenv("PORTS", "2;3")
+	defer os.Unsetenv("HOSTS")
+	defer os.Unsetenv("PORTS")
+
+	o := &struct {
+		Hosts []string `sep:",append"`
+		Ports []int    `sep:";,append"`
+	}{Hosts: []string{"a"}, Ports: []int{1}}
+	err := NewEnvLoader().Load(nil, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, o.Hosts)
+	assert.Equal(t, []int{1, 2, 3}, o.Ports)
+
+	// Generated templates use the separator without the append option.
+	b, err := NewEnvUnloader().Unload(node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o))
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "export PORTS=[1;2;3]")
+}
+
+func TestEnvLoader_SliceJSON(t *testing.T) {
+	os.Setenv("NAMES", `["a,b", "c"]`)
+	defer os.Unsetenv("NAMES")
+
+	o := &struct {
+		Names []string `sep:",json"`
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+	err := NewEnvLoader().Load(nil, nGrps)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a,b", "c"}, o.Names)
+
+	// Generated templates write a quoted JSON array.
+	b, err := NewEnvUnloader().Unload(nGrps)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `export NAMES="[\"a,b\",\"c\"]"`)
+}
+
+func TestEnvLoader_RecordNames(t *testing.T) {
+	o := &struct {
+		Host string
+		Port int `env:"-"`
+		DB   struct {
+			User string
+		}
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+
+	err := NewEnvLoader().WithPrefix("app").RecordNames(nGrps)
+	assert.NoError(t, err)
+	assert.Equal(t, "APP_HOST", nGrps[0].Map()["Host"].GetMeta("env"))
+	assert.Equal(t, "", nGrps[0].Map()["Port"].GetMeta("env"))
+	assert.Equal(t, "APP_DB_USER", nGrps[0].Map()["DB.User"].GetMeta("env"))
+}
+
+func TestEnvLoader_Fallbacks(t *testing.T) {
+	os.Setenv("APP_DB_OLD_HOST", "old")
+	defer os.Unsetenv("APP_DB_OLD_HOST")
+
+	o := &struct {
+		DB struct {
+			Host string `env:"HOST|OLD_HOST"`
+		}
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+
+	// The fallback name has the same prefix as the env name.
+	warn := &bytes.Buffer{}
+	l := NewEnvLoader().WithPrefix("app").WithWarnOutput(warn)
+	assert.NoError(t, l.Load(nil, nGrps))
+	assert.Equal(t, "old", o.DB.Host)
+	assert.Equal(t, "warning: env 'APP_DB_OLD_HOST' is deprecated: use 'APP_DB_HOST'\n", warn.String())
+
+	// The env name takes precedence.
+	os.Setenv("APP_DB_HOST", "new")
+	defer os.Unsetenv("APP_DB_HOST")
+	warn.Reset()
+	assert.NoError(t, l.Load(nil, nGrps))
+	assert.Equal(t, "new", o.DB.Host)
+	assert.Equal(t, "", warn.String())
+
+	// Generated templates only use the env name.
+	b, err := NewEnvUnloader().WithPrefix("app").Unload(nGrps)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "export APP_DB_HOST=new")
+	assert.NotContains(t, string(b), "OLD_HOST")
+}
+
+func TestEnvLoader_SliceSplitter(t *testing.T) {
+	os.Setenv("NAMES", "a b  c")
+	defer os.Unsetenv("NAMES")
+
+	o := &struct {
+		Names []string
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+
+	var gotSep string
+	l := NewEnvLoader().WithSliceSplitter(func(raw, sep string, isString bool) []string {
+		gotSep = sep
+		return strings.Fields(raw)
+	})
+	assert.NoError(t, l.Load(nil, nGrps))
+	assert.Equal(t, []string{"a", "b", "c"}, o.Names)
+	assert.Equal(t, ",", gotSep)
+}
+
+func TestEnvLoader_NoEnv(t *testing.T) {
+	os.Setenv("HOST", "a")
+	os.Setenv("DB_USER", "b")
+	defer os.Unsetenv("HOST")
+	defer os.Unsetenv("DB_USER")
+
+	o := &struct {
+		Host string `noenv:"true"`
+		DB   struct {
+			User string
+		} `noenv:"true"`
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+
+	assert.NoError(t, NewEnvLoader().Load(nil, nGrps))
+	assert.Equal(t, "", o.Host)
+	assert.Equal(t, "", o.DB.User)
+
+	// Excluded from generated templates.
+	b, err := NewEnvUnloader().Unload(nGrps)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "HOST")
+}
+
+func TestEnvLoader_FieldsCache(t *testing.T) {
+	os.Setenv("HOST", "a")
+	defer os.Unsetenv("HOST")
+
+	o := &struct{ Host string }{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+
+	l := NewEnvLoader()
+	assert.NoError(t, l.Load(nil, nGrps))
+	assert.Equal(t, "a", o.Host)
+
+	// Cached fields are reused for the same node groups.
+	os.Setenv("HOST", "b")
+	assert.NoError(t, l.Load(nil, nGrps))
+	assert.Equal(t, "b", o.Host)
+
+	// Changing the prefix clears the cache.
+	os.Setenv("APP_HOST", "c")
+	defer os.Unsetenv("APP_HOST")
+	assert.NoError(t, l.WithPrefix("app").Load(nil, nGrps))
+	assert.Equal(t, "c", o.Host)
+}
+
+func TestEnvLoader_WithEnviron(t *testing.T) {
+	os.Setenv("APP_HOST", "from-os")
+	defer os.Unsetenv("APP_HOST")
+
+	o := &struct {
+		Host  string
+		Port  int
+		Names []string
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+
+	l := NewEnvLoader().WithPrefix("app").WithEnviron([]string{
+		"APP_HOST=a=b",
+		"APP_NAMES=x,y",
+		"PORT=80", // no prefix
+		"=C:=C:\\",
+	})
+	assert.NoError(t, l.Load(nil, nGrps))
+	assert.Equal(t, "a=b", o.Host)
+	assert.Equal(t, 0, o.Port)
+	assert.Equal(t, []string{"x", "y"}, o.Names)
+
+	assert.Equal(t, map[string]string{"APP_HOST": "a=b"}, snapshotEnv([]string{"APP_HOST=a=b", "HOST=c", "BAD"}, "APP_"))
+}
+
+func TestEnvLoader_WithIndexedSlices(t *testing.T) {
+	o := &struct {
+		Hosts []string
+		Ports []int `sep:",append"`
+		Names []string
+		Tags  []string
+	}{Ports: []int{80}}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+
+	l := NewEnvLoader().WithPrefix("app").WithIndexedSlices().WithEnviron([]string{
+		"APP_HOSTS_0=a,b",
+		"APP_HOSTS_1=c",
+		"APP_HOSTS_3=gap",
+		"APP_PORTS_0=443",
+		"APP_NAMES=x,y", // no indexed vars
+		"APP_TAGS=ignored",
+		"APP_TAGS_0=t",
+	})
+	assert.NoError(t, l.Load(nil, nGrps))
+	assert.Equal(t, []string{"a,b", "c"}, o.Hosts)
+	assert.Equal(t, []int{80, 443}, o.Ports)
+	assert.Equal(t, []string{"x", "y"}, o.Names)
+	assert.Equal(t, []string{"t"}, o.Tags)
+
+	err := NewEnvLoader().WithIndexedSlices().WithEnviron([]string{"PORTS_0=abc"}).Load(nil, nGrps)
+	assert.Error(t, err)
+
+	// Not enabled.
+	o.Hosts = nil
+	assert.NoError(t, NewEnvLoader().WithPrefix("app").WithEnviron([]string{"APP_HOSTS_0=a"}).Load(nil, nGrps))
+	assert.Nil(t, o.Hosts)
+}
+
+func TestEnvLoader_WithWarnUnused(t *testing.T) {
+	o := &struct {
+		Host  string
+		Port  int
+		Hosts []string
+		DB    struct {
+			Name string `env:"NAME|DATABASE"`
+		}
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+
+	buf := &bytes.Buffer{}
+	l := NewEnvLoader().WithPrefix("app").WithIndexedSlices().WithWarnOutput(&bytes.Buffer{}).WithWarnUnused(buf).WithEnviron([]string{
+		"APP_HOST=a",
+		"APP_PORT=", // set but empty
+		"APP_HOSTS_0=b",
+		"APP_HOSTS_2=gap",
+		"APP_DB_DATABASE=db",
+		"APP_HOTS=typo",
+		"HOTS=no prefix",
+		"APPLE_X=other prefix",
+		"APPDATA=other prefix",
+	})
+	assert.NoError(t, l.Load(nil, nGrps))
+	assert.Equal(t, "db", o.DB.Name)
+	assert.Equal(t, "warning: env 'APP_HOSTS_2' is not used by any field\n"+
+		"warning: env 'APP_HOTS' is not used by any field\n", buf.String())
+
+	// Names read outside of the loader are not reported.
+	buf.Reset()
+	l = NewEnvLoader().WithPrefix("app").WithWarnUnused(buf).WithUsedNames("APP_CONFIG", "").WithEnviron([]string{
+		"APP_CONFIG=config.toml",
+		"APP_HOTS=typo",
+	})
+	assert.NoError(t, l.Load(nil, nGrps))
+	assert.Equal(t, "warning: env 'APP_HOTS' is not used by any field\n", buf.String())
+
+	// No prefix.
+	buf.Reset()
+	assert.NoError(t, NewEnvLoader().WithWarnUnused(buf).WithEnviron([]string{"HOTS=typo"}).Load(nil, nGrps))
+	assert.Empty(t, buf.String())
+}
+
+// BenchmarkEnvLoader_Load loads a large config repeatedly with the same
+// node groups (cached env fields) and with new loaders (fields generated every time).
+func BenchmarkEnvLoader_Load(b *testing.B) {
+	fields := make([]reflect.StructField, 0, 200)
+	for i := 0; i < 200; i++ {
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("FieldName%d", i),
+			Type: reflect.TypeOf(""),
+		})
+	}
+	c := reflect.New(reflect.StructOf([]reflect.StructField{
+		{Name: "DB", Type: reflect.StructOf(fields)},
+	})).Interface()
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, c)
+
+	b.Run("cached", func(b *testing.B) {
+		l := NewEnvLoader().WithPrefix("app")
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := l.Load(nil, nGrps); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := NewEnvLoader().WithPrefix("app").Load(nil, nGrps); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
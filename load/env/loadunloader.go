@@ -4,8 +4,8 @@ import "github.com/pcelvng/go-config/util/node"
 
 func NewEnvLoadUnloader() *EnvLoadUnloader {
 	return &EnvLoadUnloader{
-		loader:   &EnvLoader{},
-		unloader: &EnvUnloader{},
+		loader:   NewEnvLoader(),
+		unloader: NewEnvUnloader(),
 	}
 }
 
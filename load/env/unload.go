@@ -3,39 +3,100 @@ package env
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/pcelvng/go-config/util/node"
 )
 
 func NewEnvUnloader() *EnvUnloader {
-	return &EnvUnloader{}
+	return &EnvUnloader{nameSep: defaultNameSep, nameCase: caseUpper}
 }
 
 func (u *EnvUnloader) WithPrefix(prefix string) *EnvUnloader {
-	u.prefix = strings.ToUpper(prefix)
+	u.prefix = prefix
+	return u
+}
+
+// WithSeparator sets the separator placed between the prefix and each
+// struct level of the env name. Should match the EnvLoader separator so generated
+// and loaded names agree. The default separator is "_".
+func (u *EnvUnloader) WithSeparator(sep string) *EnvUnloader {
+	u.nameSep = sep
+	return u
+}
+
+// WithCase sets the case of generated env names. Should match the EnvLoader case
+// so generated and loaded names agree. See EnvLoader.WithCase for the options.
+func (u *EnvUnloader) WithCase(nameCase string) *EnvUnloader {
+	u.nameCase = nameCase
+	return u
+}
+
+// WithCollisionStrategy sets how env names generated by more than one field are resolved.
+// Should match the EnvLoader strategy so generated and loaded names agree. See
+// EnvLoader.WithCollisionStrategy for the options.
+func (u *EnvUnloader) WithCollisionStrategy(strategy string) *EnvUnloader {
+	u.collision = strategy
 	return u
 }
 
 func (u *EnvUnloader) Unload(nss []*node.Nodes) ([]byte, error) {
-	u.buf = &bytes.Buffer{}
+	buf := &bytes.Buffer{}
+	if err := u.UnloadTo(buf, nss); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnloadTo implements the load.StreamUnloader interface for writing
+// the env template to w.
+//
+// An error is returned if two fields generate the same env name (unless
+// a different collision strategy is set with WithCollisionStrategy).
+func (u *EnvUnloader) UnloadTo(w io.Writer, nss []*node.Nodes) error {
+	if err := checkNameCase(u.nameCase); err != nil {
+		return err
+	}
+
+	if err := checkCollisionStrategy(u.collision); err != nil {
+		return err
+	}
+
+	names, err := genNames(u.prefix, u.nameSep, u.nameCase, u.collision, nss)
+	if err != nil {
+		return err
+	}
+	u.w = w
+	u.names = names
 
 	// Write env preamble.
-	fmt.Fprint(u.buf, "#!/usr/bin/env sh\n\n")
+	if _, err := fmt.Fprint(u.w, "#!/usr/bin/env sh\n\n"); err != nil {
+		return err
+	}
 
 	for _, ns := range nss {
 		err := u.unload(ns)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return u.buf.Bytes(), nil
+	return nil
 }
 
 type EnvUnloader struct {
-	buf    *bytes.Buffer
-	prefix string
+	w        io.Writer
+	prefix   string
+	nameSep  string
+	nameCase string
+
+	// collision is the name collision strategy (see WithCollisionStrategy).
+	collision string
+
+	// names contains the full env name of each node being unloaded (see genNames).
+	names map[*node.Node]string
 }
 
 func (u *EnvUnloader) unload(nodes *node.Nodes) error {
@@ -58,13 +119,28 @@ func (u *EnvUnloader) unload(nodes *node.Nodes) error {
 			continue
 		}
 
+		// Skip file only fields (such as slices of structs) since they
+		// cannot be expressed as a single value.
+		if n.IsFileOnly() {
+			continue
+		}
+
 		// Validate that "omitprefix" is not used on value fields.
 		if getEnvTag(n) == "omitprefix" {
 			return fmt.Errorf("'omitprefix' cannot be used on non-struct field types")
 		}
 
-		// Write line bytes to buffer.
-		u.doWrite(genFullName(u.prefix, n, heritage), genHelp(n), toStr(n))
+		// Skip fields left out by the collision strategy.
+		name, ok := u.names[n]
+		if !ok {
+			continue
+		}
+
+		// Write line bytes to the writer.
+		err := u.doWrite(name, genHelp(n), toStr(n))
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -94,10 +170,12 @@ func genHelp(n *node.Node) string {
 // value to a string as it would be represented as an env value.
 //
 // The value includes double quotes for fields with the ",string"
-// env tag suffix.
+// env tag suffix or values that must be quoted (such as multi-line values).
 func toStr(n *node.Node) string {
 	if n.IsTime() {
 		return n.TimeString(n.GetTag(fmtTag))
+	} else if n.IsSliceJSON() {
+		return quoteDouble(n.ValueString())
 	} else if n.IsSlice() {
 		vals := n.SliceString()
 		if isEnvString(n) {
@@ -110,16 +188,17 @@ func toStr(n *node.Node) string {
 	}
 
 	val := n.String()
-	if isEnvString(n) {
-		val = `"` + val + `"`
+	if isEnvString(n) || needsQuote(val) {
+		val = quoteDouble(val)
 	}
 
 	return val
 }
 
-func (u *EnvUnloader) doWrite(field, comment string, value interface{}) {
+func (u *EnvUnloader) doWrite(field, comment string, value interface{}) error {
 	if comment != "" {
 		comment = " # " + comment
 	}
-	fmt.Fprintf(u.buf, "export %s=%v%v\n", field, value, comment)
+	_, err := fmt.Fprintf(u.w, "export %s=%v%v\n", field, value, comment)
+	return err
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/pcelvng/go-config/util/node"
 
 	"github.com/jbsmith7741/trial"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestEncoder_Marshal(t *testing.T) {
@@ -213,3 +214,154 @@ export PREFIX_INT_64=5
 	}
 	trial.New(fn, cases).Test(t)
 }
+
+func TestEncoder_Marshal_Separator(t *testing.T) {
+	type DB struct {
+		Host string
+	}
+
+	fn := func(args ...interface{}) (interface{}, error) {
+		b, err := NewEnvUnloader().WithPrefix("myapp").WithSeparator(".").Unload(node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, args[0]))
+		return string(b), err
+	}
+	cases := trial.Cases{
+		"nested": {
+			Input: &struct {
+				DB      DB
+				MaxConn int
+			}{
+				DB:      DB{Host: "localhost"},
+				MaxConn: 2,
+			},
+			Expected: `#!/usr/bin/env sh
+
+export MYAPP.DB.HOST=localhost
+export MYAPP.MAX_CONN=2
+`,
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestEncoder_Marshal_RoundTrip(t *testing.T) {
+	type Options struct {
+		Key  string
+		Note string
+		Name string
+	}
+
+	in := &Options{
+		Key:  "-----BEGIN KEY-----\nabc\n-----END KEY-----",
+		Note: `say "hi" $HOME \ bye`,
+		Name: "app",
+	}
+	b, err := NewEnvUnloader().Unload(node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, in))
+	assert.NoError(t, err)
+	assert.Equal(t, `#!/usr/bin/env sh
+
+export KEY="-----BEGIN KEY-----
+abc
+-----END KEY-----"
+export NOTE="say \"hi\" \$HOME \\ bye"
+export NAME=app
+`, string(b))
+
+	out := &Options{}
+	err = NewEnvLoader().Load(b, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, out))
+	assert.NoError(t, err)
+	assert.Equal(t, in, out)
+}
+
+func TestEncoder_Marshal_NestedPrefix(t *testing.T) {
+	type Pool struct {
+		Size  int
+		Limit struct {
+			Max int
+		} `env:"limits"`
+	}
+
+	type Mid struct {
+		Pool Pool `env:"omitprefix"`
+		Name string
+	}
+
+	fn := func(args ...interface{}) (interface{}, error) {
+		b, err := NewEnvUnloader().WithPrefix("app").Unload(node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, args[0]))
+		return string(b), err
+	}
+	cases := trial.Cases{
+		"omitprefix mid-tree": {
+			Input: &struct {
+				DB Mid `env:"DATABASE"`
+			}{},
+			Expected: `#!/usr/bin/env sh
+
+export APP_DATABASE_SIZE=0
+export APP_DATABASE_limits_MAX=0
+export APP_DATABASE_NAME=
+`,
+		},
+		"omitprefix top level": {
+			Input: &struct {
+				Pool Pool `env:"omitprefix"`
+			}{},
+			Expected: `#!/usr/bin/env sh
+
+export APP_SIZE=0
+export APP_limits_MAX=0
+`,
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestEnvUnloader_WithCase(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		b, err := NewEnvUnloader().WithPrefix("app").WithCase(args[0].(string)).Unload(node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, &struct {
+			LogLevel string
+			Username string `env:"UN"`
+		}{}))
+		return string(b), err
+	}
+	cases := trial.Cases{
+		"upper": {
+			Input: "upper",
+			Expected: `#!/usr/bin/env sh
+
+export APP_LOG_LEVEL=
+export APP_UN=
+`,
+		},
+		"lower": {
+			Input: "lower",
+			Expected: `#!/usr/bin/env sh
+
+export app_log_level=
+export app_un=
+`,
+		},
+		"asis": {
+			Input: "asis",
+			Expected: `#!/usr/bin/env sh
+
+export app_LogLevel=
+export app_UN=
+`,
+		},
+		"unknown": {
+			Input:     "camel",
+			ShouldErr: true,
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/pcelvng/go-config/util"
@@ -13,27 +14,77 @@ import (
 )
 
 var (
-	flagTag = "flag"
-	fmtTag  = "fmt"
-	helpTag = "help"
-	sepTag  = "sep"
+	flagTag   = "flag"
+	hiddenTag = "hidden"
+	fileOpt   = "file"
+	fmtTag    = "fmt"
+	helpTag   = "help"
+	sepTag    = "sep"
+	ignoreTag = "ignore"
+	noFlagTag = "noflag" // excludes the field from the flag loader only.
+	configTag = "config" // Expected general config values (only "ignore" supported ATM).
+
+	deprecatedTag = "deprecated"
+
+	envMeta = "env" // Node meta key of the env name.
 
 	defaultSep = ","
+
+	collisionError  = "error"               // flag names defined more than once return an error (default).
+	collisionPrefix = "prefix-disambiguate" // colliding flag names include all parent names.
+	collisionFirst  = "first-wins"          // the later field has no flag.
+
+	// nameSeps contains the struct level name separator by name format.
+	nameSeps = map[string]string{
+		"kebab": "-",
+		"snake": "_",
+		"asis":  ".",
+	}
 )
 
 // newFlagSet creates a new flagset and sets the flags.
 // The resulting flagset is useful for both getting the flag
 // help page bytes and setting values runtime flags.
 func newFlagSet(o Options, nGrps []*node.Nodes) (fs *flagSet, err error) {
+	errHandling := flag.ExitOnError
+	if o.ContinueOnError {
+		errHandling = flag.ContinueOnError
+	}
+
 	fs = &flagSet{
-		fs:      flag.NewFlagSet(os.Args[0], flag.ExitOnError),
+		fs:      flag.NewFlagSet(os.Args[0], errHandling),
 		fGroups: make([][]*Flag, 0),
 		fNames:  make(map[string]bool),
+		hidden:  make(map[string]bool),
 		options: o,
 	}
 
 	if fs.options.HelpFunc == nil {
-		fs.options.HelpFunc = defaultGenHelp
+		cols, err := util.ParseWidth(fs.options.HelpWidth)
+		if err != nil {
+			return nil, fmt.Errorf("help width: %w", err)
+		}
+
+		fs.options.HelpFunc = func(preamble, conclusion string, fGroups [][]*Flag) string {
+			return genHelp(cols, o.ShowEnvInHelp, preamble, conclusion, fGroups)
+		}
+	}
+
+	if fs.options.NameFormat == "" {
+		fs.options.NameFormat = "kebab"
+	}
+	if _, ok := nameSeps[fs.options.NameFormat]; !ok {
+		return nil, fmt.Errorf("unknown flag name format '%v'", fs.options.NameFormat)
+	}
+
+	switch fs.options.CollisionStrategy {
+	case "", collisionError, collisionPrefix, collisionFirst:
+	default:
+		return nil, fmt.Errorf("unknown flag name collision strategy '%v'", fs.options.CollisionStrategy)
+	}
+
+	if fs.options.CollisionStrategy == collisionPrefix {
+		fs.collided = collidedNames(fs.prefix, fs.options.NameFormat, nGrps)
 	}
 
 	for _, nGrp := range nGrps {
@@ -56,8 +107,36 @@ type flagSet struct {
 	fs      *flag.FlagSet
 	fGroups [][]*Flag
 	fNames  map[string]bool
+	hidden  map[string]bool // Hidden flag names; registered but excluded from the help menu.
 	options Options
 	prefix  string
+
+	// collided contains the flag names generated by more than one field. Used
+	// by the "prefix-disambiguate" collision strategy.
+	collided map[string]bool
+}
+
+// collidedNames returns the flag names generated by more than one field of "nGrps".
+func collidedNames(prefix, nameFormat string, nGrps []*node.Nodes) map[string]bool {
+	counts := make(map[string]int)
+	for _, nGrp := range nGrps {
+		for _, n := range nGrp.List() {
+			heritage := node.Parents(n, nGrp.Map())
+			if isAnyIgnored(append(heritage, n)) || (n.IsStruct() && !n.IsTime()) || n.IsFileOnly() {
+				continue
+			}
+			counts[genFullName(prefix, nameFormat, n, heritage)]++
+		}
+	}
+
+	collided := make(map[string]bool)
+	for name, count := range counts {
+		if count > 1 {
+			collided[name] = true
+		}
+	}
+
+	return collided
 }
 
 // SetHelp will override an existing field "help" value or create
@@ -72,13 +151,50 @@ type flagSet struct {
 //}
 
 func (fs *flagSet) registerHelpMenu() {
-	helpMenu := fs.options.HelpFunc(fs.options.HelpPreamble, fs.options.HelpPostamble, fs.fGroups)
+	helpMenu := fs.options.HelpFunc(fs.options.HelpPreamble, fs.options.HelpPostamble, fs.visibleGroups())
 
 	fs.fs.Usage = func() {
 		fmt.Fprint(os.Stderr, helpMenu)
 	}
 }
 
+// redactErr returns "err" with the raw values of failed secret flags
+// redacted. The flag package reports a bad value as 'invalid value "raw"
+// for flag' so the escaped (%q) form is redacted too.
+func (fs *flagSet) redactErr(err error) error {
+	for _, fGroup := range fs.fGroups {
+		for _, f := range fGroup {
+			if len(f.failed) == 0 {
+				continue
+			}
+			vals := make([]string, 0, len(f.failed)*2)
+			for _, v := range f.failed {
+				q := strconv.Quote(v)
+				vals = append(vals, v, q[1:len(q)-1])
+			}
+			err = f.n.RedactErr(err, vals...)
+		}
+	}
+
+	return err
+}
+
+// visibleGroups returns the flag groups without hidden flags.
+func (fs *flagSet) visibleGroups() [][]*Flag {
+	fGroups := make([][]*Flag, 0, len(fs.fGroups))
+	for _, fGroup := range fs.fGroups {
+		visible := make([]*Flag, 0, len(fGroup))
+		for _, f := range fGroup {
+			if !fs.hidden[f.Name] {
+				visible = append(visible, f)
+			}
+		}
+		fGroups = append(fGroups, visible)
+	}
+
+	return fGroups
+}
+
 // flagSet registers flags to the underlying flagset and
 // created underlying flags.
 func (fs *flagSet) makeFlags(nGrp *node.Nodes) error {
@@ -102,13 +218,19 @@ func (fs *flagSet) makeFlags(nGrp *node.Nodes) error {
 			continue
 		}
 
+		// Skip file only fields (such as slices of structs) since they
+		// cannot be expressed as a single value.
+		if n.IsFileOnly() {
+			continue
+		}
+
 		// Validate that "omitprefix" is not used on value fields.
 		if getFlagTag(n) == "omitprefix" {
 			return fmt.Errorf("'omitprefix' cannot be used on non-struct field types")
 		}
 
 		// Get alias if exists.
-		_, alias := nodeFlagName(n)
+		_, alias := nodeFlagName(n, fs.options.NameFormat)
 
 		// "alias" must be no more than one character.
 		if len(alias) > 1 {
@@ -116,9 +238,10 @@ func (fs *flagSet) makeFlags(nGrp *node.Nodes) error {
 		}
 
 		f := &Flag{
-			Name:  genFullName(fs.prefix, n, heritage),
+			Name:  genFullName(fs.prefix, fs.options.NameFormat, n, heritage),
 			Alias: alias,
 			n:     n,
+			split: fs.options.SliceSplitter,
 		}
 
 		// Check if is on ignore list.
@@ -126,13 +249,28 @@ func (fs *flagSet) makeFlags(nGrp *node.Nodes) error {
 			continue
 		}
 
-		// If the flag name or it's alias is already defined then return an error.
+		// Flag names generated by more than one field use the qualified
+		// name (see Options.CollisionStrategy).
+		if fs.collided[f.Name] {
+			f.Name = genQualifiedName(fs.prefix, fs.options.NameFormat, n, heritage)
+		}
+
+		// If the flag name or it's alias is already defined then return an
+		// error (unless the collision strategy is "first-wins").
+		if fs.fNames[f.Name] && fs.options.CollisionStrategy == collisionFirst {
+			continue
+		}
 		if fs.fNames[f.Name] {
 			return errors.New(fmt.Sprintf("flag name '%v' defined more than once", f.Name))
 		}
 
 		if fs.fNames[f.Alias] {
-			return errors.New(fmt.Sprintf("flag alias '%v' defined more than once", f.Alias))
+			switch fs.options.CollisionStrategy {
+			case collisionPrefix, collisionFirst:
+				f.Alias = ""
+			default:
+				return errors.New(fmt.Sprintf("flag alias '%v' defined more than once", f.Alias))
+			}
 		}
 
 		// Register name(s)
@@ -151,19 +289,12 @@ func (fs *flagSet) register(f *Flag) {
 	if f.Alias != "" {
 		fs.fNames[f.Alias] = true
 	}
-
-	// bools need to be registered separately because command
-	// line behavior is different.
-	if f.n.IsBool() {
-		bp := f.n.FieldValue.Addr().Interface().(*bool)
-		fs.fs.BoolVar(bp, f.Name, f.n.FieldValue.Bool(), "")
-
-		if f.Alias != "" {
-			fs.fs.BoolVar(bp, f.Alias, f.n.FieldValue.Bool(), "")
-		}
-		return
+	if isHidden(f.n) {
+		fs.hidden[f.Name] = true
 	}
 
+	// Note: bool flags are registered the same as other flags. The
+	// Flag "IsBoolFlag" method tells the flag package a value is optional.
 	fs.fs.Var(f, f.Name, "")
 	if f.Alias != "" {
 		fs.fs.Var(f, f.Alias, "")
@@ -174,7 +305,9 @@ type Flag struct {
 	Name  string // full flag name
 	Alias string // flag alias - if exists
 
-	n *node.Node
+	n      *node.Node
+	split  func(raw, sep string, isString bool) []string // custom slice splitter (optional)
+	failed []string                                      // raw values of failed secret sets (see flagSet.redactErr)
 }
 
 // String implements flag.ValueBefore interface and gets
@@ -185,12 +318,47 @@ func (f *Flag) String() string {
 
 // Set implements flag.ValueBefore interface and sets the
 // struct field value.
+//
+// When the flag tag has the ",file" option "s" is a file path and the
+// file contents (trimmed of surrounding whitespace) are set instead.
 func (f *Flag) Set(s string) error {
-	return set(f.n, s)
+	raw := s
+	if isFlagFile(f.n) && s != "" {
+		b, err := os.ReadFile(s)
+		if err != nil {
+			return fmt.Errorf("flag '%v': %w", f.Name, err)
+		}
+		s = strings.TrimSpace(string(b))
+	}
+
+	err := set(f.n, s, f.split)
+	if err != nil && f.n.IsSecret() {
+		f.failed = append(f.failed, raw, s)
+	}
+
+	return err
+}
+
+// IsBoolFlag implements the optional flag package "boolFlag" interface.
+//
+// When true the flag does not require a value. That is, "--flag" alone is
+// the same as "--flag=true". Note that a bool flag value must be provided
+// with "=" (i.e. "--flag=false") since "--flag false" is read as "--flag"
+// followed by the non-flag argument "false".
+func (f *Flag) IsBoolFlag() bool {
+	return f.n.IsBool()
 }
 
+// Help returns the flag help text with the "{name}" and "{default}"
+// placeholders expanded to the flag name and current (default) value.
 func (f *Flag) Help() string {
-	return f.n.GetTag(helpTag)
+	return util.ExpandHelp(f.n.GetTag(helpTag), f.Name, f.String())
+}
+
+// EnvName returns the env variable name of the flag field when
+// recorded in the node "env" meta. Otherwise an empty string is returned.
+func (f *Flag) EnvName() string {
+	return f.n.GetMeta(envMeta)
 }
 
 // ValueType returns the string representation of the type
@@ -200,10 +368,11 @@ func (f *Flag) ValueType() string {
 }
 
 // set sets the field value. It takes into account
-// special cases such as time.Time and slices.
+// special cases such as time.Time and slices. Slice values are split with
+// "split" when not nil (see Options.SliceSplitter).
 //
 // If 'flagVal' is empty then nothing is set and nil is returned.
-func set(n *node.Node, flagVal string) error {
+func set(n *node.Node, flagVal string, split func(raw, sep string, isString bool) []string) error {
 	if n == nil {
 		return nil
 	}
@@ -215,8 +384,17 @@ func set(n *node.Node, flagVal string) error {
 	if n.IsTime() {
 		_, err := n.SetTime(flagVal, n.GetTag(fmtTag))
 		return err
+	} else if n.IsSliceJSON() {
+		return n.SetSliceJSON(flagVal)
+	}
+
+	if split == nil {
+		split = splitSlice
+	}
+	if n.IsSliceAppend() {
+		return n.SetSliceAppend(split(flagVal, getSep(n), isFlagString(n)))
 	} else if n.IsSlice() {
-		return n.SetSlice(splitSlice(flagVal, n.GetTag(sepTag), isFlagString(n)))
+		return n.SetSlice(split(flagVal, getSep(n), isFlagString(n)))
 	}
 
 	return n.SetFieldValue(flagVal)
@@ -227,7 +405,7 @@ func set(n *node.Node, flagVal string) error {
 // the result is `"1"` is read in as `1` with the quotes stripped away
 // before reading in the value.
 //
-// TODO: allow hook for a custom implementation of this function.
+// See Options.SliceSplitter for a custom implementation.
 func splitSlice(flagValue string, sep string, isString bool) []string {
 	if sep == "" {
 		sep = defaultSep
@@ -249,19 +427,36 @@ func splitSlice(flagValue string, sep string, isString bool) []string {
 }
 
 // genFullName generates the full flag name including the prefix.
-var genFullName = func(prefix string, n *node.Node, heritage []*node.Node) (fullName string) {
-	return genPrefix(prefix, append(heritage, n))
+//
+// 'nameFormat' is the Options.NameFormat value.
+var genFullName = func(prefix, nameFormat string, n *node.Node, heritage []*node.Node) (fullName string) {
+	return genPrefix(prefix, nameFormat, append(heritage, n))
+}
+
+// genQualifiedName generates the full flag name including the prefix and the names
+// of all parents. Parents with the 'omitprefix' flag tag use the field name.
+func genQualifiedName(prefix, nameFormat string, n *node.Node, heritage []*node.Node) string {
+	return genName(prefix, nameFormat, append(heritage, n), true)
 }
 
 // genPrefix generates the flag name prefix.
 //
 // 'heritage' is expected to be ordered from most to least distant relative.
-func genPrefix(globalPrefix string, heritage []*node.Node) (prefix string) {
+func genPrefix(globalPrefix, nameFormat string, heritage []*node.Node) (prefix string) {
+	return genName(globalPrefix, nameFormat, heritage, false)
+}
+
+// genName joins the global prefix and the flag names of 'heritage'. When 'qualified'
+// is true nodes with the 'omitprefix' flag tag use the field name instead of being skipped.
+func genName(globalPrefix, nameFormat string, heritage []*node.Node, qualified bool) (prefix string) {
 	if globalPrefix != "" {
 		prefix = globalPrefix
 	}
 	for _, hn := range heritage {
-		flagName, _ := nodeFlagName(hn)
+		flagName, _ := nodeFlagName(hn, nameFormat)
+		if flagName == "" && qualified {
+			flagName = fieldFlagName(hn, nameFormat)
+		}
 		if flagName == "" {
 			continue
 		}
@@ -269,7 +464,7 @@ func genPrefix(globalPrefix string, heritage []*node.Node) (prefix string) {
 		if prefix == "" {
 			prefix = flagName
 		} else {
-			prefix += "-" + flagName
+			prefix += nameSeps[nameFormat] + flagName
 		}
 	}
 
@@ -278,7 +473,10 @@ func genPrefix(globalPrefix string, heritage []*node.Node) (prefix string) {
 
 // nodeFlagName generates the flag name of the node. Does
 // not include the prefix and optionally returns an alias name.
-func nodeFlagName(n *node.Node) (name, alias string) {
+//
+// When no 'flag' tag name is provided the name is the field name
+// formatted according to 'nameFormat' (see Options.NameFormat).
+func nodeFlagName(n *node.Node, nameFormat string) (name, alias string) {
 	flagVal := getFlagTag(n)
 
 	vals := strings.Split(flagVal, ",")
@@ -291,12 +489,24 @@ func nodeFlagName(n *node.Node) (name, alias string) {
 	case "omitprefix":
 		return "", ""
 	case "":
-		return util.ToKebab(n.FieldName()), ""
+		return fieldFlagName(n, nameFormat), ""
 	default:
 		return name, alias
 	}
 }
 
+// fieldFlagName generates the flag name of the node from the field name
+// formatted according to 'nameFormat' (see Options.NameFormat).
+func fieldFlagName(n *node.Node, nameFormat string) string {
+	switch nameFormat {
+	case "snake":
+		return util.ToSnake(n.FieldName())
+	case "asis":
+		return n.FieldName()
+	}
+	return util.ToKebab(n.FieldName())
+}
+
 // isAnyIgnored checks if any members of 'nodes' is ignored.
 // if so, then returns true.
 func isAnyIgnored(nodes []*node.Node) bool {
@@ -310,8 +520,18 @@ func isAnyIgnored(nodes []*node.Node) bool {
 }
 
 // isIgnored checks if the node is ignored.
+//
+// A node is ignored when one or more of the following struct
+// field tag cases are met:
+// - `ignore:"true"`
+// - `config:"ignore"`
+// - `flag:"-"`
+// - `noflag:"true"`
 func isIgnored(n *node.Node) bool {
-	if getFlagTag(n) == "-" {
+	if n.GetBoolTag(ignoreTag) ||
+		n.GetTag(configTag) == "ignore" ||
+		n.GetBoolTag(noFlagTag) ||
+		getFlagTag(n) == "-" {
 		return true
 	}
 
@@ -319,21 +539,55 @@ func isIgnored(n *node.Node) bool {
 }
 
 // getFlagTag returns the 'flag' tag value. It
-// knows to exclude the supported ',string' suffix option if present.
+// knows to exclude the supported ',string', ',hidden' and ',file'
+// suffix options if present.
 func getFlagTag(n *node.Node) string {
-	return strings.Replace(n.GetTag(flagTag), ",string", "", -1)
+	v := strings.Replace(n.GetTag(flagTag), ",string", "", -1)
+	v = strings.Replace(v, ","+fileOpt, "", -1)
+	return strings.Replace(v, ","+hiddenTag, "", -1)
+}
+
+// isHidden returns true when the flag tag has the ",hidden" option or
+// the 'hidden:"true"' tag is set. Hidden flags are registered but not
+// shown in the help menu.
+func isHidden(n *node.Node) bool {
+	for _, opt := range strings.Split(n.GetTag(flagTag), ",")[1:] {
+		if opt == hiddenTag {
+			return true
+		}
+	}
+
+	return n.GetBoolTag(hiddenTag)
+}
+
+// isFlagFile returns true when the flag tag value has the ",file" option. The
+// flag value is then read from the provided file path.
+func isFlagFile(n *node.Node) bool {
+	for _, opt := range strings.Split(n.GetTag(flagTag), ",")[1:] {
+		if opt == fileOpt {
+			return true
+		}
+	}
+
+	return false
 }
 
-// isFlagString returns true when the flag tag value has the suffix ",string".
+// isFlagString returns true when the flag tag value has the ",string" option.
 func isFlagString(n *node.Node) bool {
-	return strings.HasSuffix(n.GetTag(flagTag), ",string")
+	for _, opt := range strings.Split(n.GetTag(flagTag), ",")[1:] {
+		if opt == "string" {
+			return true
+		}
+	}
+
+	return false
 }
 
 // getSep returns the separator designed to be used for
 // the struct field node if one is provided. If a separator is not
 // provided then the default separator is returned.
 func getSep(n *node.Node) string {
-	sep := n.GetTag(sepTag)
+	sep := n.SliceSep()
 	if sep == "" {
 		sep = defaultSep
 	}
@@ -349,6 +603,8 @@ func getSep(n *node.Node) string {
 func toStr(n *node.Node) string {
 	if n.IsTime() {
 		return n.TimeString(n.GetTag(fmtTag))
+	} else if n.IsSliceJSON() {
+		return n.ValueString()
 	} else if n.IsSlice() {
 		vals := n.SliceString()
 		if isFlagString(n) {
@@ -371,7 +627,13 @@ func toStr(n *node.Node) string {
 type GenHelpFunc func(preample, conclusion string, fGrps [][]*Flag) string
 
 func defaultGenHelp(preamble, conclusion string, fGroups [][]*Flag) string {
-	cols := 175
+	return genHelp(util.DefaultWidth, false, preamble, conclusion, fGroups)
+}
+
+// genHelp generates the default help menu wrapped to "cols" columns.
+// No wrapping is done when "cols" is 0. The flag env names are included
+// when "showEnv" is true.
+func genHelp(cols int, showEnv bool, preamble, conclusion string, fGroups [][]*Flag) string {
 	helpMenu := strings.TrimRight(preamble, "\r\n") + "\r\n"
 
 	for _, fg := range fGroups {
@@ -392,6 +654,10 @@ func defaultGenHelp(preamble, conclusion string, fGroups [][]*Flag) string {
 				line += " " + varname
 			}
 
+			if dep := f.n.GetTag(deprecatedTag); dep != "" {
+				usage = strings.TrimSpace(usage + " DEPRECATED: " + dep)
+			}
+
 			// This special character will be replaced with spacing once the
 			// correct alignment is calculated
 			line += "\x00"
@@ -414,6 +680,9 @@ func defaultGenHelp(preamble, conclusion string, fGroups [][]*Flag) string {
 					usage = usage + " " + fmtV
 				}
 			}
+			if enum := f.n.EnumString(); enum != "" {
+				usage = strings.TrimSpace(usage + " " + enum)
+			}
 			line += usage
 			if usage != "" && defValue != "" {
 				line += " "
@@ -425,6 +694,13 @@ func defaultGenHelp(preamble, conclusion string, fGroups [][]*Flag) string {
 					line += fmt.Sprintf("(default: %s)", defValue)
 				}
 			}
+			if envName := f.EnvName(); showEnv && envName != "" {
+				line = strings.TrimRight(line, " ")
+				if !strings.HasSuffix(line, "\x00") {
+					line += " "
+				}
+				line += fmt.Sprintf("[env: %s]", envName)
+			}
 
 			lines = append(lines, line)
 		}
@@ -513,12 +789,13 @@ func wrapN(i, slop int, s string) (string, string) {
 func isZeroValue(valueType, strValue string) bool {
 	switch valueType {
 	case "bool":
-		return strValue == "false"
+		b, err := node.ParseBool(strValue)
+		return err == nil && !b
 	case "int", "uint", "float":
 		return strValue == "0"
 	case "string", "time":
 		return strValue == ""
-	case "bools", "durations", "ints", "uints", "strings":
+	case "bools", "durations", "floats", "ints", "uints", "strings":
 		return strValue == "[]" || strValue == ""
 	case "duration":
 		// Beginning in Go 1.7, duration zero values are "0s"
@@ -0,0 +1,464 @@
+package flag
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pcelvng/go-config/util/node"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlag_Bool(t *testing.T) {
+	type BoolOptions struct {
+		Bool    bool
+		BoolPtr *bool `flag:"bool-ptr,b"`
+	}
+
+	parse := func(args ...string) (*BoolOptions, []string, error) {
+		o := &BoolOptions{}
+		fs, err := newFlagSet(Options{}, node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, o))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = fs.fs.Parse(args)
+		return o, fs.fs.Args(), err
+	}
+
+	// "--bool" alone sets the value to true.
+	o, args, err := parse("--bool", "-b")
+	assert.NoError(t, err)
+	assert.True(t, o.Bool)
+	assert.True(t, *o.BoolPtr)
+	assert.Empty(t, args)
+
+	// Explicit values.
+	o, _, err = parse("--bool=false", "--bool-ptr=true")
+	assert.NoError(t, err)
+	assert.False(t, o.Bool)
+	assert.True(t, *o.BoolPtr)
+
+	// "--bool true" sets the value to true and "true" is
+	// a non-flag argument.
+	o, args, err = parse("--bool", "true")
+	assert.NoError(t, err)
+	assert.True(t, o.Bool)
+	assert.Equal(t, []string{"true"}, args)
+}
+
+func TestFlag_HelpPlaceholders(t *testing.T) {
+	type HelpOptions struct {
+		Host string `help:"Set --{name} to override the default {default}."`
+	}
+
+	fs, err := newFlagSet(Options{}, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, &HelpOptions{Host: "localhost"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Set --host to override the default localhost.", fs.fGroups[0][0].Help())
+}
+
+func TestFlag_NameFormat(t *testing.T) {
+	type DB struct {
+		MaxConn int
+	}
+
+	type NameOptions struct {
+		DB       DB
+		LogLevel string
+		Host     string `flag:"host-name"`
+	}
+
+	names := func(format string) ([]string, error) {
+		fs, err := newFlagSet(Options{NameFormat: format}, node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, &NameOptions{}))
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0)
+		for _, f := range fs.fGroups[0] {
+			names = append(names, f.Name)
+
+			// Flags are registered with the same name.
+			assert.NotNil(t, fs.fs.Lookup(f.Name))
+		}
+		return names, nil
+	}
+
+	n, err := names("")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"db-max-conn", "log-level", "host-name"}, n)
+
+	n, err = names("snake")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"db_max_conn", "log_level", "host-name"}, n)
+
+	n, err = names("asis")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DB.MaxConn", "LogLevel", "host-name"}, n)
+
+	_, err = names("camel")
+	assert.EqualError(t, err, "unknown flag name format 'camel'")
+}
+
+func TestFlag_NestedPrefix(t *testing.T) {
+	type Pool struct {
+		Size  int
+		Limit struct {
+			Max int
+		} `flag:"limits"`
+	}
+
+	type Mid struct {
+		Pool Pool `flag:"omitprefix"`
+		Name string
+	}
+
+	fs, err := newFlagSet(Options{}, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, &struct {
+		DB Mid `flag:"database"`
+	}{}))
+	assert.NoError(t, err)
+
+	names := make([]string, 0)
+	for _, f := range fs.fGroups[0] {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"database-size", "database-limits-max", "database-name"}, names)
+}
+
+func TestFlag_Hidden(t *testing.T) {
+	type HiddenOptions struct {
+		Host    string
+		OldHost string   `flag:"old-host,hidden"`
+		OldPort int      `hidden:"true"`
+		Names   []string `flag:"old-names,string,hidden"`
+	}
+
+	o := &HiddenOptions{}
+	fs, err := newFlagSet(Options{}, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o))
+	assert.NoError(t, err)
+
+	// Hidden flags are still parsed.
+	err = fs.fs.Parse([]string{"--old-host=a", "--old-port=1", `--old-names="b","c"`})
+	assert.NoError(t, err)
+	assert.Equal(t, &HiddenOptions{OldHost: "a", OldPort: 1, Names: []string{"b", "c"}}, o)
+
+	// Hidden flags are not in the help menu.
+	groups := fs.visibleGroups()
+	assert.Len(t, groups[0], 1)
+	assert.Equal(t, "host", groups[0][0].Name)
+
+	help := defaultGenHelp("", "", groups)
+	assert.Contains(t, help, "--host")
+	assert.NotContains(t, help, "old-")
+}
+
+func TestLoader_ContinueOnError(t *testing.T) {
+	args := os.Args
+	defer func() { os.Args = args }()
+
+	o := &struct {
+		Host string
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+
+	os.Args = []string{"app", "--hots=localhost"}
+	err := NewLoader(Options{ContinueOnError: true}).Load(nil, nGrps)
+	assert.EqualError(t, err, "invalid flags: flag provided but not defined: -hots")
+
+	os.Args = []string{"app", "--host=localhost"}
+	err = NewLoader(Options{ContinueOnError: true}).Load(nil, nGrps)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", o.Host)
+}
+
+func TestLoader_SecretParseErr(t *testing.T) {
+	o := &struct {
+		Pin  int `secret:"true"`
+		Port int
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+
+	// Secret raw values are redacted from the flag package parse error.
+	err := NewLoader(Options{Args: []string{"--pin=s3cr3t\t1234"}, ContinueOnError: true}).Load(nil, nGrps)
+	assert.EqualError(t, err, `invalid flags: invalid value "[redacted]" for flag -pin: field 'Pin': strconv.ParseInt: parsing "[redacted]": invalid syntax`)
+
+	// Non-secret values are kept.
+	err = NewLoader(Options{Args: []string{"--port=eighty"}, ContinueOnError: true}).Load(nil, nGrps)
+	assert.EqualError(t, err, `invalid flags: invalid value "eighty" for flag -port: field 'Port': strconv.ParseInt: parsing "eighty": invalid syntax`)
+}
+
+func TestLoader_Args(t *testing.T) {
+	args := os.Args
+	defer func() { os.Args = args }()
+	os.Args = []string{"app", "--host=os"}
+
+	o := &struct {
+		Host string
+		Port int
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+
+	l := NewLoader(Options{Args: []string{"--host=args", "--port=80"}})
+	err := l.Load(nil, nGrps)
+	assert.NoError(t, err)
+	assert.Equal(t, "args", o.Host)
+	assert.Equal(t, 80, o.Port)
+	assert.True(t, l.IsSet("host"))
+	assert.False(t, l.IsSet("nope"))
+
+	// An empty (non-nil) list parses no arguments.
+	o.Host = ""
+	err = NewLoader(Options{Args: []string{}}).Load(nil, nGrps)
+	assert.NoError(t, err)
+	assert.Equal(t, "", o.Host)
+}
+
+func TestLoader_Ignored(t *testing.T) {
+	o := &struct {
+		Host  string `ignore:"true"`
+		Port  int    `config:"ignore"`
+		Debug bool   `flag:"-"`
+		Level string `noflag:"true"`
+		Name  string
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+
+	for _, arg := range []string{"--host=localhost", "--port=80", "--debug", "--level=info"} {
+		err := NewLoader(Options{ContinueOnError: true, Args: []string{arg}}).Load(nil, nGrps)
+		assert.Error(t, err, arg)
+	}
+
+	err := NewLoader(Options{ContinueOnError: true, Args: []string{"--name=app"}}).Load(nil, nGrps)
+	assert.NoError(t, err)
+	assert.Equal(t, "app", o.Name)
+}
+
+func TestLoader_SliceSplitter(t *testing.T) {
+	o := &struct {
+		Names []string `sep:";"`
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+
+	var gotSep string
+	err := NewLoader(Options{
+		Args: []string{"--names=a b  c"},
+		SliceSplitter: func(raw, sep string, isString bool) []string {
+			gotSep = sep
+			return strings.Fields(raw)
+		},
+	}).Load(nil, nGrps)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, o.Names)
+	assert.Equal(t, ";", gotSep)
+}
+
+func TestLoader_Check(t *testing.T) {
+	o := &struct {
+		Host string `flag:"name"`
+		Name string
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+
+	err := NewLoader(Options{}).Check(nGrps)
+	assert.EqualError(t, err, "flag name 'name' defined more than once")
+}
+
+func TestLoader_CollisionStrategy(t *testing.T) {
+	type DB struct {
+		Host string `flag:"host,a"`
+	}
+
+	type options struct {
+		DB   DB `flag:"omitprefix"`
+		Host string
+		Name string `flag:"name,a"`
+	}
+
+	load := func(strategy string, args ...string) (*options, error) {
+		o := &options{}
+		nGrps := node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, o)
+		return o, NewLoader(Options{
+			CollisionStrategy: strategy,
+			ContinueOnError:   true,
+			Args:              args,
+		}).Load(nil, nGrps)
+	}
+
+	_, err := load("error")
+	assert.EqualError(t, err, "flag name 'host' defined more than once")
+
+	// The later alias is dropped.
+	o, err := load("prefix-disambiguate", "-a=db-host", "--host=host", "--name=name")
+	assert.NoError(t, err)
+	assert.Equal(t, &options{DB: DB{Host: "db-host"}, Host: "host", Name: "name"}, o)
+
+	o, err = load("first-wins", "--host=host")
+	assert.NoError(t, err)
+	assert.Equal(t, &options{DB: DB{Host: "host"}}, o)
+
+	_, err = load("last-wins")
+	assert.EqualError(t, err, "unknown flag name collision strategy 'last-wins'")
+}
+
+func TestFlag_HelpWidth(t *testing.T) {
+	help := strings.Repeat("word ", 30)
+	o := &struct {
+		Host string `help:"x"`
+	}{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o)
+	nGrps[0].SetTag("Host", "help", help)
+
+	genHelp := func(width string) (string, error) {
+		fs, err := newFlagSet(Options{HelpWidth: width}, nGrps)
+		if err != nil {
+			return "", err
+		}
+		return fs.options.HelpFunc("", "", fs.visibleGroups()), nil
+	}
+
+	// Default width fits on one line.
+	h, err := genHelp("")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, strings.Count(strings.TrimSpace(h), "\n"))
+
+	// Narrow width wraps.
+	h, err = genHelp("60")
+	assert.NoError(t, err)
+	assert.Greater(t, strings.Count(strings.TrimSpace(h), "\n"), 0)
+
+	// No wrapping.
+	h, err = genHelp("0")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, strings.Count(strings.TrimSpace(h), "\n"))
+
+	_, err = genHelp("narrow")
+	assert.EqualError(t, err, "help width: invalid width 'narrow'")
+}
+
+func TestFlag_Deprecated(t *testing.T) {
+	fs, err := newFlagSet(Options{}, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, &struct {
+		Host    string
+		OldHost string `help:"The host." deprecated:"use --host instead"`
+	}{}))
+	assert.NoError(t, err)
+
+	help := fs.options.HelpFunc("", "", fs.visibleGroups())
+	assert.Contains(t, help, "The host. DEPRECATED: use --host instead")
+}
+
+func TestFlag_File(t *testing.T) {
+	type FileOptions struct {
+		Token string `flag:"token,file"`
+		Key   string `flag:"key,k,file"`
+		Host  string
+	}
+
+	dir := t.TempDir()
+	pth := filepath.Join(dir, "token")
+	assert.NoError(t, os.WriteFile(pth, []byte("  secret\n"), 0600))
+
+	o := &FileOptions{}
+	fs, err := newFlagSet(Options{ContinueOnError: true}, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, o))
+	assert.NoError(t, err)
+
+	// The ",file" option is not an alias.
+	assert.Equal(t, "", fs.fGroups[0][0].Alias)
+	assert.Equal(t, "k", fs.fGroups[0][1].Alias)
+
+	err = fs.fs.Parse([]string{"--token=" + pth, "-k", pth, "--host=" + pth})
+	assert.NoError(t, err)
+	assert.Equal(t, &FileOptions{Token: "secret", Key: "secret", Host: pth}, o)
+
+	err = fs.fs.Parse([]string{"--token=" + filepath.Join(dir, "missing")})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "flag 'token': open ")
+}
+
+func TestFlag_EnumInHelp(t *testing.T) {
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, &struct {
+		Level  string `help:"The log level." enum:"debug,info,warn"`
+		Format string `enum:"json,text"`
+	}{Level: "info"})
+
+	fs, err := newFlagSet(Options{}, nGrps)
+	assert.NoError(t, err)
+	help := fs.options.HelpFunc("", "", fs.visibleGroups())
+	assert.Contains(t, help, `The log level. (one of: debug|info|warn) (default: "info")`)
+	assert.Contains(t, help, `(one of: json|text)`)
+}
+
+func TestLoader_Help(t *testing.T) {
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, &struct {
+		Host string `help:"The host."`
+	}{})
+
+	help, err := NewLoader(Options{HelpPreamble: "usage: app", HelpPostamble: "bye"}).Help(nGrps)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(help, "usage: app"))
+	assert.Contains(t, help, "--host string   The host.")
+	assert.True(t, strings.HasSuffix(help, "bye"))
+
+	_, err = NewLoader(Options{NameFormat: "bad"}).Help(nGrps)
+	assert.EqualError(t, err, "unknown flag name format 'bad'")
+}
+
+func TestFlag_ShowEnvInHelp(t *testing.T) {
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, &struct {
+		Host string `help:"The host."`
+		Name string
+		Port int
+	}{Host: "localhost"})
+	nGrps[0].Map()["Host"].SetMeta("env", "APP_HOST")
+	nGrps[0].Map()["Name"].SetMeta("env", "APP_NAME")
+
+	genHelp := func(showEnv bool) string {
+		fs, err := newFlagSet(Options{ShowEnvInHelp: showEnv}, nGrps)
+		assert.NoError(t, err)
+		return fs.options.HelpFunc("", "", fs.visibleGroups())
+	}
+
+	help := genHelp(true)
+	assert.Contains(t, help, `The host. (default: "localhost") [env: APP_HOST]`)
+	assert.Contains(t, help, " [env: APP_NAME]")
+	assert.Equal(t, 2, strings.Count(help, "[env: "))
+
+	assert.NotContains(t, genHelp(false), "[env: ")
+}
@@ -1,6 +1,8 @@
 package flag
 
 import (
+	"flag"
+	"fmt"
 	"os"
 
 	"github.com/pcelvng/go-config/util"
@@ -18,6 +20,56 @@ type Options struct {
 	// HelpFunc defines an optional custom help screen help menu render function to override the
 	// default.
 	HelpFunc GenHelpFunc
+
+	// NameFormat defines how flag names are generated from struct field names when
+	// no 'flag' tag name is provided.
+	//
+	// Options are:
+	// - "kebab" // kebab-case with struct levels separated by "-" (default). For example, "db-max-conn".
+	// - "snake" // snake_case with struct levels separated by "_". For example, "db_max_conn".
+	// - "asis" // exact field name with struct levels separated by ".". For example, "DB.MaxConn".
+	NameFormat string
+
+	// CollisionStrategy defines how flag names (and aliases) defined by more than one
+	// field are resolved. Options are:
+	// - "error" // Load returns an error (default).
+	// - "prefix-disambiguate" // the flag names include the names of all parents
+	// (including 'omitprefix' parents). For example, "db-host" instead of "host".
+	// - "first-wins" // the first field keeps the name and the later field has no flag.
+	//
+	// Aliases defined more than once are dropped from the later flag unless the strategy is "error".
+	CollisionStrategy string
+
+	// ContinueOnError will have Load return an error for invalid flags (such as an
+	// unknown flag) instead of exiting the process. The help flags ("-h", "--help", "help"
+	// and "h") print the help menu and return flag.ErrHelp.
+	ContinueOnError bool
+
+	// HelpWidth is the column width the default help menu is wrapped to. Options are:
+	// - "" // 175 columns (default).
+	// - "0" // no wrapping.
+	// - "auto" // the terminal width (175 columns when not a terminal).
+	// - a number of columns. For example, "80".
+	//
+	// Not used with a custom HelpFunc.
+	HelpWidth string
+
+	// ShowEnvInHelp will add the env variable name of each flag (for example, "[env: DB_HOST]")
+	// to the default help menu. Env names are only known when recorded in the node
+	// "env" meta (see env.EnvLoader.RecordNames). go-config records them when the env
+	// loader is used.
+	ShowEnvInHelp bool
+
+	// SliceSplitter optionally replaces how slice flag values are split into items.
+	// "sep" is the field separator ("," by default) and "isString" is true for fields
+	// with the ",string" flag tag option. The default splitting trims spaces and
+	// surrounding "[]" (and quotes when "isString" is true).
+	SliceSplitter func(raw, sep string, isString bool) []string
+
+	// Args are the command line arguments (without the program name) parsed instead
+	// of os.Args[1:] when not nil. Useful for tests and for callers that already
+	// have the arguments.
+	Args []string
 }
 
 func NewLoader(o Options) *Loader {
@@ -32,8 +84,15 @@ func (l *Loader) WithPrefix(prefix string) *Loader {
 type Loader struct {
 	o      Options
 	prefix string
+
+	// set contains the (full) names of the flags provided to the most recent Load.
+	set map[string]bool
 }
 
+// Load parses the command line flags (os.Args or Options.Args) into nGrps.
+//
+// Arguments of the form "@path" are replaced with the arguments read from the
+// response file at "path" (see util.SplitArgs for the quoting, escaping and comment rules).
 func (l *Loader) Load(_ []byte, nGrps []*node.Nodes) error {
 	fs, err := newFlagSet(l.o, nGrps)
 	if err != nil {
@@ -44,11 +103,62 @@ func (l *Loader) Load(_ []byte, nGrps []*node.Nodes) error {
 	// -help and -h are already reserved. The following
 	// provides more support for "help" and "h"
 	// without the dash "-" prefix.
-	argList := os.Args[1:]
+	argList, err := expandResponseFiles(l.o.args())
+	if err != nil {
+		return err
+	}
 	if len(argList) > 0 && (argList[0] == "help" || argList[0] == "h") {
 		fs.fs.Usage()
+		if l.o.ContinueOnError {
+			return flag.ErrHelp
+		}
 		os.Exit(0)
 	}
 
-	return fs.fs.Parse(os.Args[1:])
+	if err := fs.fs.Parse(argList); err != nil {
+		return fmt.Errorf("invalid flags: %w", fs.redactErr(err))
+	}
+
+	l.set = make(map[string]bool)
+	fs.fs.Visit(func(fl *flag.Flag) {
+		if f, ok := fl.Value.(*Flag); ok {
+			l.set[f.Name] = true
+		}
+	})
+
+	return nil
+}
+
+// IsSet returns true when the flag "name" (the full flag name, not the alias) was
+// provided to the most recent Load. Useful to tell an explicit flag value from the
+// default when both are the same.
+func (l *Loader) IsSet(name string) bool {
+	return l.set[name]
+}
+
+// Check creates the flags of nGrps without parsing any arguments and returns the
+// first flag definition problem found (such as a flag name defined more than once).
+func (l *Loader) Check(nGrps []*node.Nodes) error {
+	_, err := newFlagSet(l.o, nGrps)
+	return err
+}
+
+// Help returns the help menu of the flags of nGrps (the same menu printed by --help)
+// without parsing any arguments.
+func (l *Loader) Help(nGrps []*node.Nodes) (string, error) {
+	fs, err := newFlagSet(l.o, nGrps)
+	if err != nil {
+		return "", err
+	}
+
+	return fs.options.HelpFunc(fs.options.HelpPreamble, fs.options.HelpPostamble, fs.visibleGroups()), nil
+}
+
+// args returns the arguments to parse.
+func (o Options) args() []string {
+	if o.Args != nil {
+		return o.Args
+	}
+
+	return os.Args[1:]
 }
@@ -0,0 +1,41 @@
+package flag
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pcelvng/go-config/util"
+)
+
+// expandResponseFiles replaces each argument of the form "@path" with the arguments
+// read from the response file at "path" (see util.SplitArgs). Arguments after the
+// "--" terminator and a lone "@" are not expanded.
+//
+// Response files are not expanded recursively. That is, "@path" arguments within a
+// response file are used as is.
+func expandResponseFiles(args []string) ([]string, error) {
+	expanded := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			return append(expanded, args[i:]...), nil
+		}
+
+		if len(arg) < 2 || arg[0] != '@' {
+			expanded = append(expanded, arg)
+			continue
+		}
+
+		b, err := os.ReadFile(arg[1:])
+		if err != nil {
+			return nil, fmt.Errorf("response file: %w", err)
+		}
+
+		fArgs, err := util.SplitArgs(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("response file '%v': %w", arg[1:], err)
+		}
+		expanded = append(expanded, fArgs...)
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,22 @@
+package flag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandResponseFiles(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "args.txt")
+	err := os.WriteFile(pth, []byte("--host=a\n--port=80\n"), 0644)
+	assert.NoError(t, err)
+
+	args, err := expandResponseFiles([]string{"-v", "@" + pth, "@", "--", "@" + pth})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"-v", "--host=a", "--port=80", "@", "--", "@" + pth}, args)
+
+	_, err = expandResponseFiles([]string{"@missing.txt"})
+	assert.Error(t, err)
+}
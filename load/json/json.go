@@ -1,11 +1,21 @@
 package json
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
 
+	"github.com/pcelvng/go-config/util"
 	"github.com/pcelvng/go-config/util/node"
 )
 
+// indent is the indentation of unloaded JSON configs.
+const indent = "  "
+
 func NewJSONLoadUnloader() *JSONLoadUnloader {
 	return &JSONLoadUnloader{}
 }
@@ -14,30 +24,255 @@ func NewJSONLoadUnloader() *JSONLoadUnloader {
 type JSONLoadUnloader struct{}
 
 // Load implements the Loader interface for loading a JSON config.
-func (_ JSONLoadUnloader) Load(b []byte, nGrps []*node.Nodes) error {
+//
+// time.Duration values can be a duration string (i.e. "1m30s" or "01:30:00") or a number of nanoseconds.
+//
+// Keys not matched to a struct field are set on the 'config:",rest"' field (if any).
+func (j JSONLoadUnloader) Load(b []byte, nGrps []*node.Nodes) error {
 	for _, nGrp := range nGrps {
+		nb, err := j.parseDurations(b, nGrp)
+		if err != nil {
+			return err
+		}
+
 		// Provide the underlying struct directly since this is
 		// not a custom implementation relying on a third party.
-		err := json.Unmarshal(b, nGrp.StructPtr())
+		err = json.Unmarshal(nb, nGrp.StructPtr())
 		if err != nil {
 			return err
 		}
+
+		if util.HasRest(nGrp.StructPtr()) {
+			doc, err := j.LoadMap(b)
+			if err != nil {
+				return err
+			}
+
+			if err := util.SetRest(nGrp.StructPtr(), doc, restKey, true); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+// LoadMap implements the MapLoader interface for loading a JSON config
+// into a generic map.
+func (_ JSONLoadUnloader) LoadMap(b []byte) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Section implements the SectionLoader interface for selecting the "name" top
+// level section of a JSON config.
+func (_ JSONLoadUnloader) Section(b []byte, name string) ([]byte, error) {
+	sections := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(b, &sections); err != nil {
+		return nil, err
+	}
+
+	section, ok := sections[name]
+	if !ok {
+		return nil, fmt.Errorf("section '%v' not found", name)
+	}
+
+	return section, nil
+}
+
 // Unload implements the Unloader interface for unloading a JSON config.
-func (_ JSONLoadUnloader) Unload(nGrps []*node.Nodes) ([]byte, error) {
-	allB := make([]byte, 0)
+func (j JSONLoadUnloader) Unload(nGrps []*node.Nodes) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := j.UnloadTo(buf, nGrps); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnloadTo implements the StreamUnloader interface for writing a JSON config to w.
+//
+// The JSON is indented with two spaces and ends with a newline. Keys are in
+// struct field order and the 'config:",rest"' field values (if any) are
+// written as keys of the struct the field belongs to (sorted).
+func (j JSONLoadUnloader) UnloadTo(w io.Writer, nGrps []*node.Nodes) error {
 	for _, nGrp := range nGrps {
-		b, err := json.MarshalIndent(nGrp.StructPtr(), "", "\t")
+		var v interface{} = nGrp.StructPtr()
+		if util.HasRest(v) {
+			var err error
+			if v, err = j.restDoc(v); err != nil {
+				return err
+			}
+		}
+
+		b, err := json.MarshalIndent(v, "", indent)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		b = append(b, '\n')
+
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restDoc returns the struct pointer "v" as a generic map with
+// the 'config:",rest"' field values merged in.
+func (j JSONLoadUnloader) restDoc(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := j.LoadMap(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, util.MergeRest(v, doc, restKey, true)
+}
+
+// parseDurations returns "b" with the duration string values of the time.Duration
+// fields of "nGrp" replaced by the number of nanoseconds since encoding/json only
+// reads durations as numbers. "b" is returned as-is when there is nothing to replace.
+func (_ JSONLoadUnloader) parseDurations(b []byte, nGrp *node.Nodes) ([]byte, error) {
+	var doc map[string]interface{}
+	replaced := false
+	for _, n := range nGrp.List() {
+		if !isDuration(n.FieldValue.Type()) {
+			continue
 		}
 
-		allB = append(allB, b...)
+		// Numbers are kept as json.Number so other values are unchanged when re-encoded.
+		if doc == nil {
+			dec := json.NewDecoder(bytes.NewReader(b))
+			dec.UseNumber()
+			if err := dec.Decode(&doc); err != nil {
+				return nil, err
+			}
+		}
+
+		parent, key, ok := lookupParent(doc, append(node.Parents(n, nGrp.Map()), n))
+		if !ok {
+			continue
+		}
+
+		switch v := parent[key].(type) {
+		case string:
+			d, err := node.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("field '%v': %w", n.FullName(), err)
+			}
+			parent[key] = int64(d)
+			replaced = true
+		case []interface{}:
+			for i, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					continue
+				}
+
+				d, err := node.ParseDuration(s)
+				if err != nil {
+					return nil, fmt.Errorf("field '%v': %w", n.FullName(), err)
+				}
+				v[i] = int64(d)
+				replaced = true
+			}
+		}
+	}
+
+	if !replaced {
+		return b, nil
+	}
+
+	return json.Marshal(doc)
+}
+
+// lookupParent returns the "doc" map containing the value of the last
+// node in "heritage" and its key. Keys are matched case-insensitively.
+func lookupParent(doc map[string]interface{}, heritage []*node.Node) (map[string]interface{}, string, bool) {
+	parent := doc
+	for i, n := range heritage {
+		key, inline := restKey(n.Field)
+		if inline {
+			continue
+		}
+		if key == "" {
+			return nil, "", false
+		}
+
+		docKey, ok := foldKey(parent, key)
+		if !ok {
+			return nil, "", false
+		}
+		if i == len(heritage)-1 {
+			return parent, docKey, true
+		}
+
+		if parent, ok = parent[docKey].(map[string]interface{}); !ok {
+			return nil, "", false
+		}
+	}
+
+	return nil, "", false
+}
+
+// foldKey returns the "doc" key matching "key" preferring an exact match
+// over a case-insensitive one (as encoding/json does).
+func foldKey(doc map[string]interface{}, key string) (string, bool) {
+	if _, ok := doc[key]; ok {
+		return key, true
+	}
+
+	for k := range doc {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+
+	return "", false
+}
+
+// isDuration returns true for time.Duration, time.Duration slice
+// and time.Duration pointer types.
+func isDuration(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	return t == reflect.TypeOf(time.Duration(0))
+}
+
+// restKey implements util.RestKeyFunc with the encoding/json field key rules.
+func restKey(f reflect.StructField) (key string, inline bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	key = strings.Split(tag, ",")[0]
+	if key == "" && f.Anonymous && isStruct(f.Type) {
+		return "", true
+	}
+	if key == "" {
+		key = f.Name
+	}
+
+	return key, false
+}
+
+func isStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
 	}
 
-	return allB, nil
+	return t.Kind() == reflect.Struct
 }
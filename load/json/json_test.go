@@ -1,7 +1,10 @@
 package json
 
 import (
+	"bytes"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/jbsmith7741/trial"
 	"github.com/pcelvng/go-config/util/node"
@@ -31,3 +34,207 @@ func TestLoad(t *testing.T) {
 	}
 	trial.New(fn, cases).Test(t)
 }
+
+type Server struct {
+	Host string
+	Port int
+}
+
+type SliceStruct struct {
+	Name    string
+	Servers []Server
+}
+
+var bJSONSlice = []byte(`{
+  "name": "json",
+  "servers": [
+    {"host": "a", "port": 1},
+    {"host": "b", "port": 2}
+  ]
+}`)
+
+func TestLoad_StructSlice(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		c := &SliceStruct{}
+		err := NewJSONLoadUnloader().Load(args[0].([]byte), node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, c))
+		return c, err
+	}
+	cases := trial.Cases{
+		"array of objects": {
+			Input: bJSONSlice,
+			Expected: &SliceStruct{
+				Name:    "json",
+				Servers: []Server{{Host: "a", Port: 1}, {Host: "b", Port: 2}},
+			},
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestLoad_Duration(t *testing.T) {
+	type Server struct {
+		Timeout time.Duration
+	}
+	type DurationStruct struct {
+		Timeout  time.Duration
+		Retries  []time.Duration
+		Server   Server
+		Interval *time.Duration
+	}
+	fn := func(args ...interface{}) (interface{}, error) {
+		c := &DurationStruct{}
+		err := NewJSONLoadUnloader().Load(args[0].([]byte), node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, c))
+		return c, err
+	}
+	interval := 5 * time.Second
+	cases := trial.Cases{
+		"strings": {
+			Input: []byte(`{"timeout": "1m30s", "retries": ["1s", "2s"], "interval": "5s", "server": {"Timeout": "1m"}}`),
+			Expected: &DurationStruct{
+				Timeout:  90 * time.Second,
+				Retries:  []time.Duration{time.Second, 2 * time.Second},
+				Server:   Server{Timeout: time.Minute},
+				Interval: &interval,
+			},
+		},
+		"nanoseconds": {
+			Input:    []byte(`{"timeout": 90000000000}`),
+			Expected: &DurationStruct{Timeout: 90 * time.Second, Interval: new(time.Duration)},
+		},
+		"clock": {
+			Input:    []byte(`{"timeout": "00:01:30", "retries": ["00:01", "00:02"]}`),
+			Expected: &DurationStruct{Timeout: 90 * time.Second, Retries: []time.Duration{time.Second, 2 * time.Second}, Interval: new(time.Duration)},
+		},
+		"invalid": {
+			Input:       []byte(`{"timeout": "90"}`),
+			ExpectedErr: errors.New("field 'Timeout': time: missing unit in duration \"90\""),
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestUnloadTo(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		nGrps := node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, args[0])
+
+		b, err := NewJSONLoadUnloader().Unload(nGrps)
+		if err != nil {
+			return nil, err
+		}
+
+		buf := &bytes.Buffer{}
+		err = NewJSONLoadUnloader().UnloadTo(buf, nGrps)
+		return buf.String() == string(b) && len(b) > 0, err
+	}
+	cases := trial.Cases{
+		"same as unload": {
+			Input:    &SimpleStruct{Name: "json", Value: 10, Enable: true},
+			Expected: true,
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestUnload(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		b, err := NewJSONLoadUnloader().Unload(node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, args[0]))
+		return string(b), err
+	}
+	cases := trial.Cases{
+		"indented in field order": {
+			Input:    &SimpleStruct{Name: "json", Value: 10, Enable: true},
+			Expected: "{\n  \"Name\": \"json\",\n  \"Value\": 10,\n  \"Enable\": true\n}\n",
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestLoadMap(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		return NewJSONLoadUnloader().LoadMap(args[0].([]byte))
+	}
+	cases := trial.Cases{
+		"json": {
+			Input: []byte(`{"name": "json", "db": {"hosts": ["a", "b"]}}`),
+			Expected: map[string]interface{}{
+				"name": "json",
+				"db":   map[string]interface{}{"hosts": []interface{}{"a", "b"}},
+			},
+		},
+		"invalid": {
+			Input:     []byte(`{"name":`),
+			ShouldErr: true,
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestSection(t *testing.T) {
+	b := []byte(`{"dev": {"name": "dev", "value": 1}, "prod": {"name": "prod", "value": 2}}`)
+	fn := func(args ...interface{}) (interface{}, error) {
+		section, err := NewJSONLoadUnloader().Section(b, args[0].(string))
+		if err != nil {
+			return nil, err
+		}
+
+		c := &SimpleStruct{}
+		err = NewJSONLoadUnloader().Load(section, node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, c))
+		return c, err
+	}
+	cases := trial.Cases{
+		"prod": {
+			Input:    "prod",
+			Expected: &SimpleStruct{Name: "prod", Value: 2},
+		},
+		"missing": {
+			Input:       "qa",
+			ExpectedErr: errors.New("section 'qa' not found"),
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestRest(t *testing.T) {
+	type RestStruct struct {
+		Name string                 `json:"name"`
+		Rest map[string]interface{} `config:",rest"`
+	}
+
+	b := []byte(`{"name": "json", "newKey": "a", "nested": {"value": 1}}`)
+	c := &RestStruct{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, c)
+	if err := NewJSONLoadUnloader().Load(b, nGrps); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &RestStruct{
+		Name: "json",
+		Rest: map[string]interface{}{"newKey": "a", "nested": map[string]interface{}{"value": float64(1)}},
+	}
+	if eq, diff := trial.Equal(c, expected); !eq {
+		t.Fatal(diff)
+	}
+
+	// Unknown keys are written back in place of the rest field.
+	out, err := NewJSONLoadUnloader().Unload(nGrps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expOut := "{\n  \"name\": \"json\",\n  \"nested\": {\n    \"value\": 1\n  },\n  \"newKey\": \"a\"\n}\n"
+	if eq, diff := trial.Equal(string(out), expOut); !eq {
+		t.Fatal(diff)
+	}
+}
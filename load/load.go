@@ -1,6 +1,10 @@
 package load
 
-import "github.com/pcelvng/go-config/util/node"
+import (
+	"io"
+
+	"github.com/pcelvng/go-config/util/node"
+)
 
 type Loader interface {
 	// Load expects a struct pointer and will read in
@@ -53,7 +57,7 @@ type Unloader interface {
 	//
 	// Unload should support the following struct tags:
 	// - config (config="ignore" ignore the field)
-	// - req (communicates if the field is required)
+	// - req or required (communicates if the field is required)
 	// - desc (field description; most likely expressed as a comment)
 	// - format specific tag (ie "env" for environment variables)
 	// - time (for time.Time field types)
@@ -77,6 +81,41 @@ type Unloader interface {
 	Unload([]*node.Nodes) ([]byte, error)
 }
 
+// StreamUnloader can optionally be implemented by an Unloader to write the
+// unloaded bytes directly to a writer instead of returning them all at once.
+// Useful for large configs.
+//
+// The bytes written to "w" should be the same as the bytes returned by Unload.
+type StreamUnloader interface {
+	UnloadTo(w io.Writer, nGrps []*node.Nodes) error
+}
+
+// MapLoader can optionally be implemented by a file Loader to read
+// a config document into a generic map without a config struct.
+//
+// Nested objects should also be represented as map[string]interface{}.
+type MapLoader interface {
+	LoadMap(b []byte) (map[string]interface{}, error)
+}
+
+// SectionLoader can optionally be implemented by a file Loader to select a
+// top level section (such as a "prod" profile) of a config document.
+//
+// Section returns the "name" section of "b" as a config document in the same format
+// so it can be passed to Load. An error is returned if the section does not exist.
+type SectionLoader interface {
+	Section(b []byte, name string) ([]byte, error)
+}
+
+// MergeUnloader can optionally be implemented by an Unloader to generate a config
+// template that is merged into an "existing" config instead of replacing it.
+//
+// The returned bytes should preserve the existing config as is (including comments)
+// and only add values missing from it.
+type MergeUnloader interface {
+	UnloadMerge(existing []byte, nGrps []*node.Nodes) ([]byte, error)
+}
+
 type LoadUnloader interface {
 	Loader
 	Unloader
@@ -0,0 +1,87 @@
+package systemd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pcelvng/go-config/util/node"
+)
+
+var (
+	credTag   = "cred"   // Expected credential struct tag name.
+	configTag = "config" // Expected general config values (only "ignore" supported ATM).
+	ignoreTag = "ignore"
+
+	credDirEnv = "CREDENTIALS_DIRECTORY" // Env variable set by systemd to the credentials directory.
+)
+
+func NewLoader() *Loader {
+	return &Loader{}
+}
+
+// Loader implements the load.Loader interface for loading systemd credentials
+// (see "LoadCredential" in systemd.exec).
+type Loader struct{}
+
+// Load reads the credential file named by the 'cred' struct tag from the
+// $CREDENTIALS_DIRECTORY directory for each tagged field. For example:
+//
+//	DBPassword string `cred:"db-password"` // reads $CREDENTIALS_DIRECTORY/db-password
+//
+// A single trailing newline is removed from the credential value.
+//
+// Load does nothing when $CREDENTIALS_DIRECTORY is not set. An error is returned
+// if a tagged credential file does not exist in the directory.
+func (l *Loader) Load(_ []byte, nGrps []*node.Nodes) error {
+	dir := os.Getenv(credDirEnv)
+	if dir == "" {
+		return nil
+	}
+
+	for _, nGrp := range nGrps {
+		for _, n := range nGrp.List() {
+			name := n.GetTag(credTag)
+			if name == "" || isAnyIgnored(append(node.Parents(n, nGrp.Map()), n)) {
+				continue
+			}
+
+			if n.IsStruct() && !n.IsTime() {
+				return fmt.Errorf("field '%v': 'cred' cannot be used on struct field types", n.FullName())
+			}
+
+			if filepath.Base(name) != name {
+				return fmt.Errorf("field '%v': invalid credential name '%v'", n.FullName(), name)
+			}
+
+			b, err := ioutil.ReadFile(filepath.Join(dir, name))
+			if os.IsNotExist(err) {
+				return fmt.Errorf("field '%v': credential '%v' not found in '%v'", n.FullName(), name, dir)
+			}
+			if err != nil {
+				return err
+			}
+
+			val := strings.TrimSuffix(strings.TrimSuffix(string(b), "\n"), "\r")
+			if err := n.SetFieldValue(val); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// isAnyIgnored checks if any of the nodes has the 'ignore:"true"' or 'config:"ignore"'
+// struct tag.
+func isAnyIgnored(nodes []*node.Node) bool {
+	for _, n := range nodes {
+		if n.GetBoolTag(ignoreTag) || n.GetTag(configTag) == "ignore" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,52 @@
+package systemd
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jbsmith7741/trial"
+	"github.com/pcelvng/go-config/util/node"
+)
+
+type Options struct {
+	Host       string
+	DBPassword string `cred:"db-password"`
+	Port       int    `cred:"port"`
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "db-password"), []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "port"), []byte("8080"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	fn := func(args ...interface{}) (interface{}, error) {
+		t.Setenv(credDirEnv, args[0].(string))
+
+		o := &Options{Host: "localhost"}
+		err := NewLoader().Load(nil, node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, o))
+		return o, err
+	}
+	cases := trial.Cases{
+		"credentials": {
+			Input:    dir,
+			Expected: &Options{Host: "localhost", DBPassword: "secret", Port: 8080},
+		},
+		"no credentials directory": {
+			Input:    "",
+			Expected: &Options{Host: "localhost"},
+		},
+		"missing credential": {
+			Input:       t.TempDir(),
+			ExpectedErr: errors.New("field 'DBPassword': credential 'db-password' not found in"),
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
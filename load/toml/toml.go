@@ -2,8 +2,13 @@ package toml
 
 import (
 	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
 
 	"github.com/hydronica/toml"
+	"github.com/pcelvng/go-config/util"
 	"github.com/pcelvng/go-config/util/node"
 )
 
@@ -15,7 +20,9 @@ func NewTOMLLoadUnloader() *TOMLLoadUnloader {
 type TOMLLoadUnloader struct{}
 
 // Load implements the Loader interface for loading a TOML config.
-func (_ TOMLLoadUnloader) Load(b []byte, nGrps []*node.Nodes) error {
+//
+// Keys not matched to a struct field are set on the 'config:",rest"' field (if any).
+func (t TOMLLoadUnloader) Load(b []byte, nGrps []*node.Nodes) error {
 	for _, nGrp := range nGrps {
 		// Provide the underlying struct directly since this is
 		// not a custom implementation relying on a third party.
@@ -23,20 +30,129 @@ func (_ TOMLLoadUnloader) Load(b []byte, nGrps []*node.Nodes) error {
 		if err != nil {
 			return err
 		}
+
+		if util.HasRest(nGrp.StructPtr()) {
+			doc, err := t.LoadMap(b)
+			if err != nil {
+				return err
+			}
+
+			if err := util.SetRest(nGrp.StructPtr(), doc, restKey, true); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+// LoadMap implements the MapLoader interface for loading a TOML config
+// into a generic map.
+func (_ TOMLLoadUnloader) LoadMap(b []byte) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	if _, err := toml.Decode(string(b), &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Section implements the SectionLoader interface for selecting the "name" top
+// level table of a TOML config. The table is re-encoded as a TOML document.
+func (_ TOMLLoadUnloader) Section(b []byte, name string) ([]byte, error) {
+	sections := make(map[string]interface{})
+	if _, err := toml.Decode(string(b), &sections); err != nil {
+		return nil, err
+	}
+
+	section, ok := sections[name]
+	if !ok {
+		return nil, fmt.Errorf("section '%v' not found", name)
+	}
+
+	table, ok := section.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("section '%v' must be a table", name)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := toml.NewEncoder(buf).Encode(table); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
 // Unload implements the Unloader interface for unloading a TOML config.
-func (_ TOMLLoadUnloader) Unload(nGrps []*node.Nodes) ([]byte, error) {
+func (t TOMLLoadUnloader) Unload(nGrps []*node.Nodes) ([]byte, error) {
 	buf := &bytes.Buffer{}
-	tEnc := toml.NewEncoder(buf)
+	if err := t.UnloadTo(buf, nGrps); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnloadTo implements the StreamUnloader interface for writing a TOML config to w.
+//
+// The 'config:",rest"' field values (if any) are written as keys of the
+// struct the field belongs to.
+func (t TOMLLoadUnloader) UnloadTo(w io.Writer, nGrps []*node.Nodes) error {
+	tEnc := toml.NewEncoder(w)
 	for _, nGrp := range nGrps {
-		err := tEnc.Encode(nGrp.StructPtr())
+		var v interface{} = nGrp.StructPtr()
+		if util.HasRest(v) {
+			var err error
+			if v, err = t.restDoc(v); err != nil {
+				return err
+			}
+		}
+
+		err := tEnc.Encode(v)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
-	return buf.Bytes(), nil
+	return nil
+}
+
+// restDoc returns the struct pointer "v" as a generic map with
+// the 'config:",rest"' field values merged in.
+func (t TOMLLoadUnloader) restDoc(v interface{}) (map[string]interface{}, error) {
+	buf := &bytes.Buffer{}
+	if err := toml.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	doc, err := t.LoadMap(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, util.MergeRest(v, doc, restKey, true)
+}
+
+// restKey implements util.RestKeyFunc with the TOML field key rules.
+func restKey(f reflect.StructField) (key string, inline bool) {
+	tag := f.Tag.Get("toml")
+	if tag == "-" {
+		return "", false
+	}
+
+	key = strings.Split(tag, ",")[0]
+	if key == "" && f.Anonymous && isStruct(f.Type) {
+		return "", true
+	}
+	if key == "" {
+		key = f.Name
+	}
+
+	return key, false
+}
+
+func isStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct
 }
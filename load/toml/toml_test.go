@@ -1,7 +1,10 @@
 package toml
 
 import (
+	"bytes"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/jbsmith7741/trial"
 	"github.com/pcelvng/go-config/util/node"
@@ -35,3 +38,239 @@ func TestLoad(t *testing.T) {
 	}
 	trial.New(fn, cases).Test(t)
 }
+
+type Server struct {
+	Host string
+	Port int
+}
+
+type SliceStruct struct {
+	Name    string
+	Servers []Server
+}
+
+var bTOMLSlice = []byte(`name = "toml"
+
+[[servers]]
+host = "a"
+port = 1
+
+[[servers]]
+host = "b"
+port = 2`)
+
+func TestLoad_StructSlice(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		c := &SliceStruct{}
+		err := NewTOMLLoadUnloader().Load(args[0].([]byte), node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, c))
+		return c, err
+	}
+	cases := trial.Cases{
+		"array of tables": {
+			Input: bTOMLSlice,
+			Expected: &SliceStruct{
+				Name:    "toml",
+				Servers: []Server{{Host: "a", Port: 1}, {Host: "b", Port: 2}},
+			},
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+type Pool struct {
+	Size int
+}
+
+type DB struct {
+	Host string
+	Port int
+	Pool Pool
+}
+
+type NestedStruct struct {
+	Name string
+	DB   DB
+	Ptr  *DB
+}
+
+func TestLoad_InlineTable(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		c := &NestedStruct{}
+		err := NewTOMLLoadUnloader().Load(args[0].([]byte), node.MakeAllNodes(node.Options{
+			NoFollow:  []string{"time.Time"},
+			NoInitNil: true,
+		}, c))
+		return c, err
+	}
+
+	// Inline tables and full tables populate the same nested structs.
+	expected := &NestedStruct{
+		Name: "toml",
+		DB:   DB{Host: "x", Port: 1, Pool: Pool{Size: 2}},
+		Ptr:  &DB{Host: "y"},
+	}
+	cases := trial.Cases{
+		"tables": {
+			Input:    []byte("name = \"toml\"\n\n[db]\nhost = \"x\"\nport = 1\n\n[db.pool]\nsize = 2\n\n[ptr]\nhost = \"y\"\n"),
+			Expected: expected,
+		},
+		"inline tables": {
+			Input:    []byte("name = \"toml\"\ndb = {host = \"x\", port = 1, pool = {size = 2}}\nptr = {host = \"y\"}\n"),
+			Expected: expected,
+		},
+		"mixed": {
+			Input:    []byte("name = \"toml\"\nptr = {host = \"y\"}\n\n[db]\nhost = \"x\"\nport = 1\npool = {size = 2}\n"),
+			Expected: expected,
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestLoad_Duration(t *testing.T) {
+	type Server struct {
+		Timeout time.Duration
+	}
+	type DurationStruct struct {
+		Timeout  time.Duration
+		Retries  []time.Duration
+		Server   Server
+		Interval *time.Duration
+	}
+	fn := func(args ...interface{}) (interface{}, error) {
+		c := &DurationStruct{}
+		err := NewTOMLLoadUnloader().Load(args[0].([]byte), node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, c))
+		return c, err
+	}
+	interval := 5 * time.Second
+	cases := trial.Cases{
+		"strings": {
+			Input: []byte("timeout = \"1m30s\"\nretries = [\"1s\", \"2s\"]\ninterval = \"5s\"\n\n[server]\ntimeout = \"1m\"\n"),
+			Expected: &DurationStruct{
+				Timeout:  90 * time.Second,
+				Retries:  []time.Duration{time.Second, 2 * time.Second},
+				Server:   Server{Timeout: time.Minute},
+				Interval: &interval,
+			},
+		},
+		"nanoseconds": {
+			Input:    []byte("timeout = 90000000000\n"),
+			Expected: &DurationStruct{Timeout: 90 * time.Second, Interval: new(time.Duration)},
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestUnloadTo(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		nGrps := node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, args[0])
+
+		b, err := NewTOMLLoadUnloader().Unload(nGrps)
+		if err != nil {
+			return nil, err
+		}
+
+		buf := &bytes.Buffer{}
+		err = NewTOMLLoadUnloader().UnloadTo(buf, nGrps)
+		return buf.String() == string(b) && len(b) > 0, err
+	}
+	cases := trial.Cases{
+		"same as unload": {
+			Input:    &SimpleStruct{Name: "toml", Value: 10, Enable: true},
+			Expected: true,
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestLoadMap(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		return NewTOMLLoadUnloader().LoadMap(args[0].([]byte))
+	}
+	cases := trial.Cases{
+		"toml": {
+			Input: []byte("name = \"toml\"\n\n[db]\nhosts = [\"a\", \"b\"]\n"),
+			Expected: map[string]interface{}{
+				"name": "toml",
+				"db":   map[string]interface{}{"hosts": []interface{}{"a", "b"}},
+			},
+		},
+		"invalid": {
+			Input:     []byte(`name = `),
+			ShouldErr: true,
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestSection(t *testing.T) {
+	b := []byte("[dev]\nname = \"dev\"\nvalue = 1\n\n[prod]\nname = \"prod\"\nvalue = 2\nenable = true\n")
+	fn := func(args ...interface{}) (interface{}, error) {
+		section, err := NewTOMLLoadUnloader().Section(b, args[0].(string))
+		if err != nil {
+			return nil, err
+		}
+
+		c := &SimpleStruct{}
+		err = NewTOMLLoadUnloader().Load(section, node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, c))
+		return c, err
+	}
+	cases := trial.Cases{
+		"prod": {
+			Input:    "prod",
+			Expected: &SimpleStruct{Name: "prod", Value: 2, Enable: true},
+		},
+		"missing": {
+			Input:       "qa",
+			ExpectedErr: errors.New("section 'qa' not found"),
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestRest(t *testing.T) {
+	type RestStruct struct {
+		Name string
+		Rest map[string]interface{} `config:",rest" toml:"-"`
+	}
+
+	b := []byte("name = \"toml\"\nnew_key = 1\n\n[extra]\nvalue = \"a\"\n")
+	c := &RestStruct{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, c)
+	if err := NewTOMLLoadUnloader().Load(b, nGrps); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &RestStruct{
+		Name: "toml",
+		Rest: map[string]interface{}{"new_key": int64(1), "extra": map[string]interface{}{"value": "a"}},
+	}
+	if eq, diff := trial.Equal(c, expected); !eq {
+		t.Fatal(diff)
+	}
+
+	// Unknown keys are written back.
+	out, err := NewTOMLLoadUnloader().Unload(nGrps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c2 := &RestStruct{}
+	if err := NewTOMLLoadUnloader().Load(out, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, c2)); err != nil {
+		t.Fatal(err)
+	}
+	if eq, diff := trial.Equal(c2, expected); !eq {
+		t.Fatal(diff)
+	}
+}
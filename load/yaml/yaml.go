@@ -1,6 +1,13 @@
 package yaml
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/pcelvng/go-config/util"
 	"github.com/pcelvng/go-config/util/node"
 	"gopkg.in/yaml.v2"
 )
@@ -13,28 +20,222 @@ func NewYAMLLoadUnloader() *YAMLLoadUnloader {
 type YAMLLoadUnloader struct{}
 
 // Load implements the Loader interface for loading a YAML config.
-func (_ YAMLLoadUnloader) Load(b []byte, nGrps []*node.Nodes) error {
+//
+// Keys not matched to a struct field are set on the 'config:",rest"' field (if any).
+func (y YAMLLoadUnloader) Load(b []byte, nGrps []*node.Nodes) error {
 	for _, nGrp := range nGrps {
 		err := yaml.Unmarshal(b, nGrp.StructPtr())
 		if err != nil {
 			return err
 		}
+
+		if util.HasRest(nGrp.StructPtr()) {
+			doc, err := y.LoadMap(b)
+			if err != nil {
+				return err
+			}
+
+			if err := util.SetRest(nGrp.StructPtr(), doc, restKey, false); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+// LoadMap implements the MapLoader interface for loading a YAML config
+// into a generic map. Nested mappings are converted to map[string]interface{}.
+func (_ YAMLLoadUnloader) LoadMap(b []byte) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	for k, v := range m {
+		m[k] = stringKeys(v)
+	}
+
+	return m, nil
+}
+
+// stringKeys converts the map[interface{}]interface{} mappings decoded by yaml
+// (including mappings in slices) to map[string]interface{}.
+func stringKeys(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[fmt.Sprint(k)] = stringKeys(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range t {
+			t[i] = stringKeys(val)
+		}
+		return t
+	}
+
+	return v
+}
+
+// Section implements the SectionLoader interface for selecting the "name" top
+// level mapping of a YAML config. The mapping is re-encoded as a YAML document.
+//
+// Note: anchors defined outside of the section can be referenced (aliases and merge
+// keys are resolved before the section is re-encoded).
+func (_ YAMLLoadUnloader) Section(b []byte, name string) ([]byte, error) {
+	sections := make(map[string]interface{})
+	if err := yaml.Unmarshal(b, &sections); err != nil {
+		return nil, err
+	}
+
+	section, ok := sections[name]
+	if !ok {
+		return nil, fmt.Errorf("section '%v' not found", name)
+	}
+
+	if _, ok := section.(map[interface{}]interface{}); !ok {
+		return nil, fmt.Errorf("section '%v' must be a mapping", name)
+	}
+
+	return yaml.Marshal(section)
+}
+
 // Unload implements the Unloader interface for unloading a YAML config.
-func (_ YAMLLoadUnloader) Unload(nGrps []*node.Nodes) ([]byte, error) {
-	allB := make([]byte, 0)
+func (y YAMLLoadUnloader) Unload(nGrps []*node.Nodes) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := y.UnloadTo(buf, nGrps); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnloadTo implements the StreamUnloader interface for writing a YAML config to w.
+//
+// The 'config:",rest"' field values (if any) are written as keys of the
+// struct the field belongs to.
+func (y YAMLLoadUnloader) UnloadTo(w io.Writer, nGrps []*node.Nodes) error {
 	for _, nGrp := range nGrps {
-		b, err := yaml.Marshal(nGrp.StructPtr())
+		var v interface{} = nGrp.StructPtr()
+		if util.HasRest(v) {
+			var err error
+			if v, err = y.restDoc(v); err != nil {
+				return err
+			}
+		}
+
+		b, err := yaml.Marshal(v)
 		if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnloadMerge implements the MergeUnloader interface. The existing YAML bytes are
+// kept as is (comments, anchors and merge keys included) and top level keys missing
+// from the existing YAML are appended to the end.
+//
+// Note: only top level keys are compared. Nested keys missing from an existing
+// top level key are not added.
+func (y YAMLLoadUnloader) UnloadMerge(existing []byte, nGrps []*node.Nodes) ([]byte, error) {
+	current := yaml.MapSlice{}
+	if err := yaml.Unmarshal(existing, &current); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[interface{}]bool)
+	for _, item := range current {
+		keys[item.Key] = true
+	}
+
+	missing := yaml.MapSlice{}
+	for _, nGrp := range nGrps {
+		var v interface{} = nGrp.StructPtr()
+		if util.HasRest(v) {
+			var err error
+			if v, err = y.restDoc(v); err != nil {
+				return nil, err
+			}
+		}
+
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+
+		gen := yaml.MapSlice{}
+		if err := yaml.Unmarshal(b, &gen); err != nil {
 			return nil, err
 		}
 
-		allB = append(allB, b...)
+		for _, item := range gen {
+			if !keys[item.Key] {
+				keys[item.Key] = true
+				missing = append(missing, item)
+			}
+		}
+	}
+
+	buf := bytes.NewBuffer(existing)
+	if len(missing) == 0 {
+		return buf.Bytes(), nil
+	}
+
+	if len(existing) > 0 && existing[len(existing)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+
+	b, err := yaml.Marshal(missing)
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(b)
+
+	return buf.Bytes(), nil
+}
+
+// restDoc returns the struct pointer "v" as a generic map with
+// the 'config:",rest"' field values merged in.
+func (y YAMLLoadUnloader) restDoc(v interface{}) (map[string]interface{}, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := y.LoadMap(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, util.MergeRest(v, doc, restKey, false)
+}
+
+// restKey implements util.RestKeyFunc with the yaml.v2 field key rules.
+func restKey(f reflect.StructField) (key string, inline bool) {
+	tag := f.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false
+	}
+
+	opts := strings.Split(tag, ",")
+	for _, opt := range opts[1:] {
+		if opt == "inline" {
+			return "", true
+		}
+	}
+
+	key = opts[0]
+	if key == "" {
+		key = strings.ToLower(f.Name)
 	}
 
-	return allB, nil
+	return key, false
 }
@@ -1,7 +1,10 @@
 package yaml
 
 import (
+	"bytes"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/jbsmith7741/trial"
 	"github.com/pcelvng/go-config/util/node"
@@ -33,3 +36,295 @@ func TestLoad(t *testing.T) {
 	}
 	trial.New(fn, cases).Test(t)
 }
+
+type Server struct {
+	Host string
+	Port int
+}
+
+type SliceStruct struct {
+	Name    string
+	Servers []Server
+}
+
+var bYAMLSlice = []byte(`name: "yaml"
+servers:
+  - host: "a"
+    port: 1
+  - host: "b"
+    port: 2`)
+
+func TestLoad_StructSlice(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		c := &SliceStruct{}
+		err := NewYAMLLoadUnloader().Load(args[0].([]byte), node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, c))
+		return c, err
+	}
+	cases := trial.Cases{
+		"sequence of mappings": {
+			Input: bYAMLSlice,
+			Expected: &SliceStruct{
+				Name:    "yaml",
+				Servers: []Server{{Host: "a", Port: 1}, {Host: "b", Port: 2}},
+			},
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+type Pool struct {
+	Size int
+}
+
+type DB struct {
+	Host string
+	Port int
+	Pool Pool
+}
+
+type NestedStruct struct {
+	Name string
+	DB   DB
+	Ptr  *DB
+}
+
+func TestLoad_FlowMapping(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		c := &NestedStruct{}
+		err := NewYAMLLoadUnloader().Load(args[0].([]byte), node.MakeAllNodes(node.Options{
+			NoFollow:  []string{"time.Time"},
+			NoInitNil: true,
+		}, c))
+		return c, err
+	}
+
+	// Flow mappings and block mappings populate the same nested structs.
+	expected := &NestedStruct{
+		Name: "yaml",
+		DB:   DB{Host: "x", Port: 1, Pool: Pool{Size: 2}},
+		Ptr:  &DB{Host: "y"},
+	}
+	cases := trial.Cases{
+		"block": {
+			Input:    []byte("name: yaml\ndb:\n  host: x\n  port: 1\n  pool:\n    size: 2\nptr:\n  host: y\n"),
+			Expected: expected,
+		},
+		"flow": {
+			Input:    []byte("name: yaml\ndb: {host: x, port: 1, pool: {size: 2}}\nptr: {host: y}\n"),
+			Expected: expected,
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestLoad_Duration(t *testing.T) {
+	type Server struct {
+		Timeout time.Duration
+	}
+	type DurationStruct struct {
+		Timeout  time.Duration
+		Retries  []time.Duration
+		Server   Server
+		Interval *time.Duration
+	}
+	fn := func(args ...interface{}) (interface{}, error) {
+		c := &DurationStruct{}
+		err := NewYAMLLoadUnloader().Load(args[0].([]byte), node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, c))
+		return c, err
+	}
+	interval := 5 * time.Second
+	cases := trial.Cases{
+		"strings": {
+			Input: []byte("timeout: 1m30s\nretries: [1s, 2s]\ninterval: 5s\nserver:\n  timeout: 1m\n"),
+			Expected: &DurationStruct{
+				Timeout:  90 * time.Second,
+				Retries:  []time.Duration{time.Second, 2 * time.Second},
+				Server:   Server{Timeout: time.Minute},
+				Interval: &interval,
+			},
+		},
+		"nanoseconds": {
+			Input:    []byte("timeout: 90000000000\n"),
+			Expected: &DurationStruct{Timeout: 90 * time.Second, Interval: new(time.Duration)},
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestUnloadTo(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		nGrps := node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, args[0])
+
+		b, err := NewYAMLLoadUnloader().Unload(nGrps)
+		if err != nil {
+			return nil, err
+		}
+
+		buf := &bytes.Buffer{}
+		err = NewYAMLLoadUnloader().UnloadTo(buf, nGrps)
+		return buf.String() == string(b) && len(b) > 0, err
+	}
+	cases := trial.Cases{
+		"same as unload": {
+			Input:    &SimpleStruct{Name: "yaml", Value: 10, Enable: true},
+			Expected: true,
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestUnloadMerge(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		nGrps := node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, &SimpleStruct{Name: "yaml", Value: 10, Enable: true})
+
+		b, err := NewYAMLLoadUnloader().UnloadMerge([]byte(args[0].(string)), nGrps)
+		return string(b), err
+	}
+	cases := trial.Cases{
+		"missing keys appended": {
+			Input:    "# app config\nname: &n custom # keep\n",
+			Expected: "# app config\nname: &n custom # keep\nvalue: 10\nenable: true\n",
+		},
+		"no trailing newline": {
+			Input:    "name: custom\nvalue: 1\nenable: false",
+			Expected: "name: custom\nvalue: 1\nenable: false",
+		},
+		"missing newline added": {
+			Input:    "name: custom",
+			Expected: "name: custom\nvalue: 10\nenable: true\n",
+		},
+		"empty": {
+			Input:    "",
+			Expected: "name: yaml\nvalue: 10\nenable: true\n",
+		},
+		"invalid yaml": {
+			Input:     "name: [",
+			ShouldErr: true,
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestLoadMap(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		return NewYAMLLoadUnloader().LoadMap(args[0].([]byte))
+	}
+	cases := trial.Cases{
+		"yaml": {
+			Input: []byte("name: yaml\ndb:\n  hosts:\n    - host: a\n      port: 1\n"),
+			Expected: map[string]interface{}{
+				"name": "yaml",
+				"db": map[string]interface{}{
+					"hosts": []interface{}{map[string]interface{}{"host": "a", "port": 1}},
+				},
+			},
+		},
+		"invalid": {
+			Input:     []byte("name: ["),
+			ShouldErr: true,
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestSection(t *testing.T) {
+	b := []byte(`defaults: &defaults
+  value: 10
+dev:
+  <<: *defaults
+  name: dev
+prod:
+  <<: *defaults
+  name: prod
+  value: 20
+list: [1, 2]
+`)
+	fn := func(args ...interface{}) (interface{}, error) {
+		section, err := NewYAMLLoadUnloader().Section(b, args[0].(string))
+		if err != nil {
+			return nil, err
+		}
+
+		c := &SimpleStruct{}
+		err = NewYAMLLoadUnloader().Load(section, node.MakeAllNodes(node.Options{
+			NoFollow: []string{"time.Time"},
+		}, c))
+		return c, err
+	}
+	cases := trial.Cases{
+		"merge key": {
+			Input:    "dev",
+			Expected: &SimpleStruct{Name: "dev", Value: 10},
+		},
+		"override": {
+			Input:    "prod",
+			Expected: &SimpleStruct{Name: "prod", Value: 20},
+		},
+		"missing": {
+			Input:       "qa",
+			ExpectedErr: errors.New("section 'qa' not found"),
+		},
+		"not a mapping": {
+			Input:       "list",
+			ExpectedErr: errors.New("section 'list' must be a mapping"),
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestRest(t *testing.T) {
+	type DB struct {
+		Host string
+		Rest map[string]interface{} `config:",rest" yaml:"-"`
+	}
+	type RestStruct struct {
+		Name string
+		DB   DB
+		Rest map[string]interface{} `config:",rest" yaml:"-"`
+	}
+
+	b := []byte("name: yaml\nnewkey: 1\ndb:\n  host: localhost\n  pool:\n    size: 2\n")
+	c := &RestStruct{}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, c)
+	if err := NewYAMLLoadUnloader().Load(b, nGrps); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &RestStruct{
+		Name: "yaml",
+		DB: DB{
+			Host: "localhost",
+			Rest: map[string]interface{}{"pool": map[string]interface{}{"size": 2}},
+		},
+		Rest: map[string]interface{}{"newkey": 1},
+	}
+	if eq, diff := trial.Equal(c, expected); !eq {
+		t.Fatal(diff)
+	}
+
+	// Unknown keys are written back.
+	out, err := NewYAMLLoadUnloader().Unload(nGrps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c2 := &RestStruct{}
+	if err := NewYAMLLoadUnloader().Load(out, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, c2)); err != nil {
+		t.Fatal(err)
+	}
+	if eq, diff := trial.Equal(c2, expected); !eq {
+		t.Fatal(diff)
+	}
+}
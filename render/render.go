@@ -3,7 +3,11 @@ package render
 import (
 	"bytes"
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pcelvng/go-config/util"
 	"github.com/pcelvng/go-config/util/node"
@@ -11,21 +15,24 @@ import (
 
 var (
 	configTag = "config"
-	reqTag    = "req"
-	showTag   = "show"
 	fmtTag    = "fmt"
 	ignoreTag = "ignore"
 	helpTag   = "help"
-	sepTag    = "sep"
 	flagTag   = "flag"
 	envTag    = "env"
 	jsonTag   = "json"
 	yamlTag   = "yaml"
 	tomlTag   = "toml"
-
-	defaultSep = ","
 )
 
+const defaultDisplaySep = ", "
+
+// sourceMeta is the node meta key of the name of the loader that set the field value.
+const sourceMeta = "source"
+
+// now returns the current time. Replaced in tests.
+var now = time.Now
+
 // New should be called before struct values are populated as
 // it marks the initial field value. The field value is marked again
 // right before rendering so that the "ValueBefore" and "ValueAfter"
@@ -36,15 +43,47 @@ func New(o Options, nGrps []*node.Nodes, prefix string) (*Renderer, error) {
 		preamble:   o.Preamble,
 		conclusion: o.Postamble,
 		prefix:     prefix,
+		displaySep: o.DisplaySep,
+		relTime:    o.RelativeTime,
+	}
+	if r.displaySep == "" {
+		r.displaySep = defaultDisplaySep
 	}
 
 	// field name generator.
-	ng := defNameGenerator{}
+	ng := defNameGenerator{flat: o.Flat}
 	ng.nameFrom, ng.formatAs = parseFieldNameFormat(o.FieldNameFormat)
 	r.nameFunc = ng.genFieldName
+	if o.NameFunc != nil {
+		r.nameFunc = func(n *node.Node, heritage []*node.Node, prefix string) string {
+			name := o.NameFunc(n, heritage)
+			if name == "" || prefix == r.prefix {
+				return name
+			}
+
+			// Struct slice item field.
+			return prefix + "." + name
+		}
+	}
+
+	// group titles
+	r.titles = groupTitles(o.GroupTitles, nGrps)
 
 	// render func
-	r.renderFunc = defaultRenderer
+	cols, err := util.ParseWidth(o.Width)
+	if err != nil {
+		return nil, fmt.Errorf("show width: %w", err)
+	}
+	r.renderFunc = func(preamble, conclusion string, fieldGroups [][]*Field) []byte {
+		titles := r.titles
+		if o.GroupBySource {
+			titles, fieldGroups = groupBySource(fieldGroups)
+		}
+		return defaultRenderer(cols, o.AlwaysShowDefault, preamble, conclusion, titles, fieldGroups)
+	}
+	if o.Flat {
+		r.renderFunc = flatRenderer
+	}
 	if o.RenderFunc != nil {
 		r.renderFunc = o.RenderFunc
 	}
@@ -58,6 +97,13 @@ func New(o Options, nGrps []*node.Nodes, prefix string) (*Renderer, error) {
 	// Record initial field values.
 	r.recordVals()
 
+	// Expand help now that default values are known.
+	for _, fGrp := range r.fGrps {
+		for _, f := range fGrp {
+			f.Help = util.ExpandHelp(f.Node.GetTag(helpTag), f.Name, f.ValueBefore)
+		}
+	}
+
 	return r, nil
 }
 
@@ -71,8 +117,19 @@ type Field struct {
 	Show        bool
 	TimeFmt     string // Effective 'fmt' value for time.Time fields.
 
+	// Help is the field 'help' tag value with the "{name}" and "{default}"
+	// placeholders expanded. The default renderer does not display help
+	// but it's available to custom RenderFuncs.
+	Help string
+
+	// Items contains a field group for each item of a struct slice
+	// ("structs" type) in slice order. Items are populated on render.
+	Items [][]*Field
+
 	Node          *node.Node
 	valueRecorded bool
+	displaySep    string
+	relTime       bool // Render time.Time values relative to now.
 }
 
 // recordValue will record the string representation of
@@ -81,24 +138,42 @@ type Field struct {
 // call writes the value to "ValueAfter".
 func (f *Field) recordValue() {
 	if f.valueRecorded {
-		f.ValueAfter = toStr(f.Node)
+		f.ValueAfter = f.toStr()
 		return
 	}
 
-	f.ValueBefore = toStr(f.Node)
+	f.ValueBefore = f.toStr()
 	f.valueRecorded = true
 }
 
+// toStr returns the display string of the field value. Shown (and unmasked)
+// time.Time values are rendered relative to now when "relTime" is true.
+func (f *Field) toStr() string {
+	n := f.Node
+	if f.relTime && n.IsTime() && n.IsShown() && n.GetTag("mask") == "" {
+		return relativeTime(n.FieldValue.Interface().(time.Time), now())
+	}
+
+	return toStr(n, f.displaySep)
+}
+
 func (f *Field) IsZero(val string) bool {
 	switch f.Type {
 	case "bool":
-		return val == "false"
-	case "int", "uint", "float":
+		b, err := node.ParseBool(val)
+		return err == nil && !b
+	case "int", "uint":
 		return val == "0"
+	case "float":
+		// Floats may be formatted with a 'fmt' tag precision (i.e. "0.00").
+		fv, err := strconv.ParseFloat(val, 64)
+		return err == nil && fv == 0
 	case "string", "time":
 		return val == ""
-	case "bools", "durations", "ints", "uints", "strings":
+	case "bools", "durations", "floats", "ints", "uints", "strings":
 		return val == "[]" || val == ""
+	case "structs":
+		return val == "0 items" || val == ""
 	case "duration":
 		// Beginning in Go 1.7, duration zero values are "0s"
 		return val == "0" || val == "0s"
@@ -136,6 +211,66 @@ type Options struct {
 	// - "[env,flag,json,toml,yaml,field] as [snake,kebab,screaming,
 	FieldNameFormat string
 
+	// NameFunc is optional and if provided fully replaces the default field name
+	// generator (FieldNameFormat is not used and the global prefix is not applied).
+	//
+	// "heritage" contains the parent struct nodes of "n" ordered from the most distant
+	// ancestor to the direct parent. It's empty for top level fields. Returning an empty
+	// string excludes the field from rendering.
+	//
+	// Item fields of struct slices are named with the struct slice field name and item
+	// index prepended. For example, "Servers[0].{name}".
+	NameFunc func(n *node.Node, heritage []*node.Node) string
+
+	// GroupTitles is optional and provides a title for each config struct (field group) in
+	// the same order the config structs are provided. Each field group is rendered
+	// with a header line such as "=== AppConfig ===".
+	//
+	// Titles not provided are derived from the struct type name. Headers are only rendered
+	// when GroupTitles is provided or there is more than one field group.
+	GroupTitles []string
+
+	// DisplaySep is optional and is the separator used to join slice values
+	// when rendered. Defaults to ", ". DisplaySep only affects rendering; slice values
+	// are still parsed using the field 'sep' tag value.
+	DisplaySep string
+
+	// Width is the column width the default renderer wraps field help text to. Options are:
+	// - "" // 175 columns (default).
+	// - "0" // no wrapping.
+	// - "auto" // the terminal width (175 columns when not a terminal).
+	// - a number of columns. For example, "80".
+	Width string
+
+	// RelativeTime will render time.Time field values relative to now (for example,
+	// "2h ago" or "in 5m") instead of with the 'fmt' layout. Only the Show output is
+	// affected. Zero times are rendered empty.
+	RelativeTime bool
+
+	// ShowVersion will prepend a header line with the application version (as set
+	// with go-config "Version") to the Show output. No header is written when the
+	// version is not set.
+	ShowVersion bool
+
+	// Flat will render one "name=value" line per field instead of the aligned
+	// block. For example, "db.host=localhost". Struct levels of the default field names
+	// are separated by "." (in the FieldNameFormat case) and values are not quoted.
+	// Redacted values are rendered as "[redacted]".
+	//
+	// Group headers, types, defaults, the "Preamble" and "Postamble" are not rendered.
+	Flat bool
+
+	// AlwaysShowDefault will render the "(default: ...)" annotation of every shown field
+	// even when the default is the zero value. Useful for auditing. Fields that are not
+	// shown (such as secrets) never render the default. Only the default renderer is affected.
+	AlwaysShowDefault bool
+
+	// GroupBySource will render the fields in sections by the loader that set the final
+	// field value instead of by config struct. Sections are rendered in the order "From flags",
+	// "From env", "From file", any other sources (such as "From override") and "Defaults" for
+	// fields not changed by any loader. Only the default renderer is affected.
+	GroupBySource bool
+
 	// RenderFunc is optional and if provided overrides the default render
 	// function. If a custom RenderFunc is provided then "Preamble" and "Postamble" are
 	// not used.
@@ -150,14 +285,84 @@ type Renderer struct {
 	fGrps      [][]*Field // One field group per config struct passed.
 	renderFunc RenderFunc
 	nameFunc   func(n *node.Node, heritage []*node.Node, prefix string) string
-	prefix     string // Global prefix.
+	prefix     string   // Global prefix.
+	titles     []string // Group titles; empty if group headers are not rendered.
+	displaySep string   // Slice value separator used for display.
+	relTime    bool     // Render time.Time values relative to now.
+}
+
+// GroupTitles returns the resolved field group titles in field group order. Empty
+// if group headers are not rendered.
+func (r *Renderer) GroupTitles() []string {
+	return r.titles
+}
+
+// groupTitles resolves the field group titles. Titles are only
+// resolved if titles are provided or there is more than one node group.
+func groupTitles(titles []string, nGrps []*node.Nodes) []string {
+	if len(titles) == 0 && len(nGrps) < 2 {
+		return []string{}
+	}
+
+	resolved := make([]string, len(nGrps))
+	for i, nGrp := range nGrps {
+		if i < len(titles) && titles[i] != "" {
+			resolved[i] = titles[i]
+			continue
+		}
+
+		resolved[i] = reflect.TypeOf(nGrp.StructPtr()).Elem().Name()
+		if resolved[i] == "" { // anonymous struct
+			resolved[i] = fmt.Sprintf("Config %d", i+1)
+		}
+	}
+
+	return resolved
 }
 
 func (r *Renderer) Render() []byte {
 	r.recordVals() // Record final string values.
+	r.recordItems()
 	return r.renderFunc(r.preamble, r.conclusion, r.fGrps)
 }
 
+// recordItems generates the item field groups of struct slice fields
+// from the final loaded values. Item field names are the
+// struct slice field name followed by the item index. For example,
+// "Servers[0].Host".
+func (r *Renderer) recordItems() {
+	for _, fGrp := range r.fGrps {
+		for _, f := range fGrp {
+			if !f.Node.IsStructSlice() {
+				continue
+			}
+
+			f.Items = make([][]*Field, 0)
+			for i, nGrp := range f.Node.ItemNodes(node.Options{NoFollow: []string{"time.Time"}}) {
+				if nGrp == nil {
+					continue
+				}
+
+				fg, err := r.fieldGroup(nGrp, fmt.Sprintf("%v[%d]", f.Name, i))
+				if err != nil {
+					continue
+				}
+
+				for _, itemF := range fg {
+					// Items inherit the parent's "show" value.
+					itemF.Show = itemF.Show && f.Show
+					itemF.ValueAfter = itemF.toStr()
+					if !itemF.Show {
+						itemF.ValueAfter = node.Redacted
+					}
+					itemF.valueRecorded = true
+				}
+				f.Items = append(f.Items, fg)
+			}
+		}
+	}
+}
+
 // recordVals records the current node string values.
 // The first time it's called the "ValueBefore" string value
 // is recorded. The second time it's called the "ValueAfter" value
@@ -174,55 +379,41 @@ func (r *Renderer) recordVals() {
 }
 
 // defaultRenderer is the default render function.
-func defaultRenderer(preamble, conclusion string, fieldGroups [][]*Field) []byte {
-	cols := 175
+//
+// When titles are provided the field group at the same index is rendered
+// with a header line. Help text is wrapped to "cols" columns (no wrapping when 0).
+// Zero default values are rendered when "alwaysDefault" is true.
+func defaultRenderer(cols int, alwaysDefault bool, preamble, conclusion string, titles []string, fieldGroups [][]*Field) []byte {
 	buf := new(bytes.Buffer)
 
 	if preamble != "" {
 		fmt.Fprintln(buf, preamble)
 	}
 
-	for _, fg := range fieldGroups {
+	for i, fg := range fieldGroups {
+		if i < len(titles) && len(fg) > 0 {
+			fmt.Fprintf(buf, "=== %s ===\n", titles[i])
+		}
+
 		lines := make([]string, 0, len(fg))
 		maxlen := 0
 		for _, f := range fg {
-			line := ""
-
-			// field name
-			//
-			// The special character will be replaced with spacing once the
-			// correct alignment is calculated
-			line += f.Name + " (" + f.Type + ")" + ":\x00"
-			if len(line) > maxlen {
-				maxlen = len(line)
-			}
-
-			// Resolved value.
-			if f.Show {
-				if f.Type == "string" {
-					line += fmt.Sprintf("%q", f.ValueAfter)
-				} else {
-					line += fmt.Sprintf("%s", f.ValueAfter)
-				}
-			} else {
-				line += "[redacted]"
+			line := fieldLine(f, true, alwaysDefault)
+			if l := strings.Index(line, "\x00") + 1; l > maxlen {
+				maxlen = l
 			}
+			lines = append(lines, line)
 
-			// Default value.
-			if !f.IsZero(f.ValueBefore) && f.Show {
-				if f.Type == "string" {
-					line += fmt.Sprintf(" (default: %q)", f.ValueBefore)
-				} else {
-					line += fmt.Sprintf(" (default: %s)", f.ValueBefore)
+			// Struct slice items are listed by index below the item count.
+			for _, itemFg := range f.Items {
+				for _, itemF := range itemFg {
+					line := fieldLine(itemF, false, false)
+					if l := strings.Index(line, "\x00") + 1; l > maxlen {
+						maxlen = l
+					}
+					lines = append(lines, line)
 				}
 			}
-
-			// required
-			if f.Req {
-				line += " (required)"
-			}
-
-			lines = append(lines, line)
 		}
 
 		for _, line := range lines {
@@ -244,6 +435,129 @@ func defaultRenderer(preamble, conclusion string, fieldGroups [][]*Field) []byte
 	return []byte("\r\n" + body + "\r\n")
 }
 
+// fileSources are the file loader names grouped as "From file" by groupBySource.
+var fileSources = []string{"toml", "yaml", "json"}
+
+// groupBySource regroups the fields of "fieldGroups" by the recorded field source (see
+// sourceMeta) and returns the section titles and field groups. Sections without fields are
+// left out.
+func groupBySource(fieldGroups [][]*Field) (titles []string, groups [][]*Field) {
+	bySource := make(map[string][]*Field)
+	others := make([]string, 0)
+	for _, fg := range fieldGroups {
+		for _, f := range fg {
+			title := sourceTitle(f.Node.GetMeta(sourceMeta))
+			if _, ok := bySource[title]; !ok {
+				others = append(others, title)
+			}
+			bySource[title] = append(bySource[title], f)
+		}
+	}
+	sort.Strings(others)
+
+	titles = make([]string, 0, len(bySource))
+	groups = make([][]*Field, 0, len(bySource))
+	add := func(title string) {
+		if fg, ok := bySource[title]; ok {
+			titles = append(titles, title)
+			groups = append(groups, fg)
+			delete(bySource, title)
+		}
+	}
+
+	for _, title := range []string{"From flags", "From env", "From file"} {
+		add(title)
+	}
+	for _, title := range others {
+		if title != "Defaults" {
+			add(title)
+		}
+	}
+	add("Defaults")
+
+	return titles, groups
+}
+
+// sourceTitle returns the section title of the field source "src".
+func sourceTitle(src string) string {
+	switch src {
+	case "":
+		return "Defaults"
+	case "flag":
+		return "From flags"
+	}
+
+	for _, fs := range fileSources {
+		if src == fs {
+			return "From file"
+		}
+	}
+
+	return "From " + src
+}
+
+// flatRenderer renders one "name=value" line per field. Struct slice
+// items are rendered instead of the item count.
+func flatRenderer(_, _ string, fieldGroups [][]*Field) []byte {
+	lines := make([]string, 0)
+	for _, fg := range fieldGroups {
+		for _, f := range fg {
+			if !f.Node.IsStructSlice() {
+				lines = append(lines, f.Name+"="+f.ValueAfter)
+				continue
+			}
+
+			for _, itemFg := range f.Items {
+				for _, itemF := range itemFg {
+					lines = append(lines, itemF.Name+"="+itemF.ValueAfter)
+				}
+			}
+		}
+	}
+
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// fieldLine generates the rendered line for a single field. The field name and
+// value are separated by the special character "\x00" which is replaced with spacing
+// once the correct alignment is calculated.
+//
+// The default value is included when "withDefault" is true and the default is not the
+// zero value (or "alwaysDefault" is true).
+func fieldLine(f *Field, withDefault, alwaysDefault bool) string {
+	line := f.Name + " (" + f.Type + ")" + ":\x00"
+
+	// Resolved value.
+	//
+	// Note: values that are not shown are already recorded as "[redacted]".
+	if f.Show && f.Type == "string" {
+		line += fmt.Sprintf("%q", f.ValueAfter)
+	} else {
+		line += fmt.Sprintf("%s", f.ValueAfter)
+	}
+
+	// Default value.
+	if withDefault && (alwaysDefault || !f.IsZero(f.ValueBefore)) && f.Show {
+		if f.Type == "string" {
+			line += fmt.Sprintf(" (default: %q)", f.ValueBefore)
+		} else {
+			line += fmt.Sprintf(" (default: %s)", f.ValueBefore)
+		}
+	}
+
+	// Allowed values.
+	if enum := f.Node.EnumString(); enum != "" {
+		line += " " + enum
+	}
+
+	// required
+	if f.Req {
+		line += " (required)"
+	}
+
+	return line
+}
+
 // wrap wraps the string `s` to a maximum width `w` with leading indent
 // `i`. The first line is not indented (this is assumed to be done by
 // caller). Pass `w` == 0 to do no wrapping
@@ -315,7 +629,7 @@ func wrapN(i, slop int, s string) (string, string) {
 func (r *Renderer) fieldGroups(ngrps []*node.Nodes) ([][]*Field, error) {
 	fgs := make([][]*Field, 0)
 	for _, ngrp := range ngrps {
-		fg, err := r.fieldGroup(ngrp)
+		fg, err := r.fieldGroup(ngrp, r.prefix)
 		if err != nil {
 			return nil, err
 		}
@@ -325,7 +639,9 @@ func (r *Renderer) fieldGroups(ngrps []*node.Nodes) ([][]*Field, error) {
 	return fgs, nil
 }
 
-func (r *Renderer) fieldGroup(ngrp *node.Nodes) ([]*Field, error) {
+// fieldGroup creates the fields for the node group. "prefix" is prepended
+// to all the generated field names.
+func (r *Renderer) fieldGroup(ngrp *node.Nodes, prefix string) ([]*Field, error) {
 	fg := make([]*Field, 0)
 	for _, n := range ngrp.List() {
 		heritage := node.Parents(n, ngrp.Map())
@@ -338,6 +654,11 @@ func (r *Renderer) fieldGroup(ngrp *node.Nodes) ([]*Field, error) {
 			continue
 		}
 
+		// Skip hidden fields ('show:"hide"') and fields of hidden structs.
+		if isAnyHidden(append(heritage, n)) {
+			continue
+		}
+
 		// Skip fields that are themselves structs (excluding special structs like time.Time).
 		//
 		// Note: for now time.Time is treated specifically. At some point we want to key
@@ -352,17 +673,19 @@ func (r *Renderer) fieldGroup(ngrp *node.Nodes) ([]*Field, error) {
 			return nil, fmt.Errorf("'omitprefix' cannot be used on non-struct field types")
 		}
 
-		name := r.nameFunc(n, heritage, r.prefix)
+		name := r.nameFunc(n, heritage, prefix)
 		if name == "" {
 			continue
 		}
 		fg = append(fg, &Field{
-			Name:    name,
-			Type:    node.ValueType(n),
-			Req:     n.GetBoolTag(reqTag),
-			Show:    isShown(n),
-			TimeFmt: timeFmt(n),
-			Node:    n,
+			Name:       name,
+			Type:       node.ValueType(n),
+			Req:        n.IsRequired(),
+			Show:       n.IsShown(),
+			TimeFmt:    timeFmt(n),
+			Node:       n,
+			displaySep: r.displaySep,
+			relTime:    r.relTime,
 		})
 	}
 
@@ -370,41 +693,46 @@ func (r *Renderer) fieldGroup(ngrp *node.Nodes) ([]*Field, error) {
 }
 
 // toStr handles the converting an existing/default field
-// value to a generic string representation.
-func toStr(n *node.Node) string {
-	if n.IsTime() {
-		return n.TimeString(n.GetTag(fmtTag))
-	} else if n.IsSlice() {
-		vals := n.SliceString()
-		return `[` + strings.Join(vals, getSep(n)) + `]`
-	}
-	val := n.String()
-
-	return val
+// value to a generic string representation for display.
+//
+// Values are redacted or masked according to the 'show' and 'mask' tags
+// and slice values are joined with "sep".
+func toStr(n *node.Node, sep string) string {
+	return n.DisplayStringSep(sep)
 }
 
-// getSep returns the separator designed to be used for
-// the struct field node if one is provided. If a separator is not
-// provided then the default separator is returned.
-func getSep(n *node.Node) string {
-	sep := n.GetTag(sepTag)
-	if sep == "" {
-		sep = defaultSep
+// relativeTime returns "t" as a duration relative to "now" in the largest
+// whole unit (days, hours, minutes or seconds). For example, "2h ago" or "in 3d".
+// Returns an empty string for the zero time.
+func relativeTime(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
 	}
 
-	return sep
-}
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
 
-// isShown calculates if a node value is shown.
-// If no value is present then defaults to "true".
-// Otherwise, takes the bool value of the "show" tag.
-func isShown(n *node.Node) bool {
-	show := n.GetTag(showTag)
-	if show == "" {
-		return true
+	var s string
+	switch {
+	case d < time.Second:
+		return "now"
+	case d < time.Minute:
+		s = fmt.Sprintf("%ds", d/time.Second)
+	case d < time.Hour:
+		s = fmt.Sprintf("%dm", d/time.Minute)
+	case d < 24*time.Hour:
+		s = fmt.Sprintf("%dh", d/time.Hour)
+	default:
+		s = fmt.Sprintf("%dd", d/(24*time.Hour))
 	}
 
-	return n.GetBoolTag(showTag)
+	if future {
+		return "in " + s
+	}
+	return s + " ago"
 }
 
 func timeFmt(n *node.Node) string {
@@ -507,6 +835,17 @@ func isAnyIgnored(nodes []*node.Node) bool {
 	return false
 }
 
+// isAnyHidden checks if any members of 'nodes' have the 'show:"hide"' tag.
+func isAnyHidden(nodes []*node.Node) bool {
+	for _, n := range nodes {
+		if n.IsHidden() {
+			return true
+		}
+	}
+
+	return false
+}
+
 // isIgnored checks if the node is ignored.
 //
 // A node is ignored when one or more of the following struct
@@ -527,11 +866,15 @@ func isIgnored(n *node.Node) bool {
 type defNameGenerator struct {
 	nameFrom string
 	formatAs string
+	flat     bool // Struct levels are separated by "." (see Options.Flat).
 }
 
 // genFieldName generates the string representation of the field name.
 func (ng *defNameGenerator) genFieldName(n *node.Node, heritage []*node.Node, prefix string) (fullName string) {
 	del := genDel(ng.formatAs)
+	if ng.flat {
+		del = "."
+	}
 	if len(prefix) > 0 {
 		prefix = prefix + del
 	}
@@ -586,7 +929,7 @@ func nodeName(n *node.Node, nameFrom, formatAs string) string {
 	case envTag, flagTag, tomlTag, yamlTag, jsonTag:
 		name = n.GetTag(nameFrom)
 		name = strings.Split(name, ",")[0] // handle cases with special "," options like ",string"
-		if name == "-" {
+		if name == "-" || name == "omitprefix" {
 			return ""
 		}
 
@@ -2,7 +2,9 @@ package render
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pcelvng/go-config/util/node"
 
@@ -35,7 +37,7 @@ func TestRender(t *testing.T) {
 		Preamble:        "my preamble",
 		Postamble:       "my conclusion",
 		FieldNameFormat: " as field",
-	}, nGrps)
+	}, nGrps, "")
 	if err != nil {
 		fmt.Println(err.Error())
 	}
@@ -47,3 +49,426 @@ func TestRender(t *testing.T) {
 	r.Render()
 	//fmt.Println(string(b))
 }
+
+func TestRender_StructSlice(t *testing.T) {
+	type Server struct {
+		Host string
+		Pass string `show:"false"`
+	}
+
+	type RenderMe struct {
+		Servers []Server
+	}
+	rm := &RenderMe{}
+
+	r, err := New(Options{}, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, rm), "")
+	assert.Nil(t, err)
+
+	rm.Servers = []Server{{Host: "a", Pass: "secret"}, {Host: "b"}}
+	b := r.Render()
+
+	expected := "\r\n" +
+		"Servers (structs):          2 items\n" +
+		"Servers[0].Host (string):   \"a\"\n" +
+		"Servers[0].Pass (string):   [redacted]\n" +
+		"Servers[1].Host (string):   \"b\"\n" +
+		"Servers[1].Pass (string):   [redacted]\r\n"
+	assert.Equal(t, expected, string(b))
+}
+
+func TestRender_Flat(t *testing.T) {
+	type Server struct {
+		Host string
+	}
+
+	type DB struct {
+		Host     string
+		Password string `show:"false"`
+	}
+
+	type RenderMe struct {
+		LogLevel string
+		DB       DB
+		Hosts    []string
+		Servers  []Server
+	}
+	rm := &RenderMe{}
+
+	r, err := New(Options{Flat: true, FieldNameFormat: "toml"}, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, rm), "app")
+	assert.Nil(t, err)
+
+	rm.LogLevel = "info"
+	rm.DB = DB{Host: "localhost", Password: "secret"}
+	rm.Hosts = []string{"a", "b"}
+	rm.Servers = []Server{{Host: "s1"}}
+
+	expected := "app.log_level=info\n" +
+		"app.db.host=localhost\n" +
+		"app.db.password=[redacted]\n" +
+		"app.hosts=[a, b]\n" +
+		"app.servers[0].host=s1"
+	assert.Equal(t, expected, string(r.Render()))
+}
+
+func TestRender_AlwaysShowDefault(t *testing.T) {
+	type RenderMe struct {
+		Host     string
+		Port     int
+		Debug    bool
+		Password string `secret:"true"`
+	}
+	rm := &RenderMe{Host: "localhost"}
+
+	r, err := New(Options{AlwaysShowDefault: true, FieldNameFormat: "env"}, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, rm), "")
+	assert.Nil(t, err)
+
+	rm.Port = 80
+	rm.Password = "secret"
+
+	out := string(r.Render())
+	assert.Contains(t, out, `"localhost" (default: "localhost")`)
+	assert.Contains(t, out, "80 (default: 0)")
+	assert.Contains(t, out, "false (default: false)")
+	assert.Contains(t, out, "[redacted]\r\n")
+
+	// Zero defaults are not rendered by default.
+	rm2 := &RenderMe{}
+	r, err = New(Options{FieldNameFormat: "env"}, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, rm2), "")
+	assert.Nil(t, err)
+	assert.NotContains(t, string(r.Render()), "default")
+}
+
+func TestRender_GroupBySource(t *testing.T) {
+	type RenderMe struct {
+		Host  string
+		Port  int
+		Name  string
+		Debug bool
+		Level string
+	}
+	rm := &RenderMe{Host: "localhost"}
+	nodes := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, rm)
+
+	r, err := New(Options{GroupBySource: true, FieldNameFormat: "env"}, nodes, "")
+	assert.Nil(t, err)
+
+	rm.Port, rm.Name, rm.Level = 80, "app", "info"
+	nodes[0].Map()["Port"].SetMeta("source", "env")
+	nodes[0].Map()["Name"].SetMeta("source", "toml")
+	nodes[0].Map()["Level"].SetMeta("source", "override")
+
+	out := string(r.Render())
+	for _, section := range []string{"=== From env ===", "=== From file ===", "=== From override ===", "=== Defaults ==="} {
+		assert.Contains(t, out, section)
+	}
+	assert.NotContains(t, out, "From flags")
+	assert.True(t, strings.Index(out, "From env") < strings.Index(out, "From file"))
+	assert.True(t, strings.Index(out, "From override") < strings.Index(out, "Defaults"))
+	assert.True(t, strings.Index(out, "PORT") < strings.Index(out, "NAME"))
+	assert.True(t, strings.Index(out, "Defaults") < strings.Index(out, "HOST"))
+	assert.True(t, strings.Index(out, "Defaults") < strings.Index(out, "DEBUG"))
+}
+
+func TestRender_Enum(t *testing.T) {
+	type RenderMe struct {
+		Level string `enum:"debug,info" req:"true"`
+	}
+
+	r, err := New(Options{FieldNameFormat: "env"}, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, &RenderMe{Level: "info"}), "")
+	assert.Nil(t, err)
+	assert.Contains(t, string(r.Render()), `"info" (default: "info") (one of: debug|info) (required)`)
+}
+
+func TestRender_HelpPlaceholders(t *testing.T) {
+	type RenderMe struct {
+		Host string `help:"{name} defaults to {default}"`
+	}
+
+	r, err := New(Options{FieldNameFormat: "env"}, node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, &RenderMe{Host: "localhost"}), "")
+	assert.Nil(t, err)
+	assert.Equal(t, "HOST defaults to localhost", r.fGrps[0][0].Help)
+}
+
+func TestRender_GroupTitles(t *testing.T) {
+	type AppConfig struct {
+		Name string
+	}
+
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, &AppConfig{}, &struct{ Port int }{})
+
+	// Titles are derived from the struct type name.
+	r, err := New(Options{}, nGrps, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"AppConfig", "Config 2"}, r.GroupTitles())
+	assert.Equal(t, "\r\n=== AppConfig ===\n"+
+		"Name (string):   \"\"\n\n"+
+		"=== Config 2 ===\n"+
+		"Port (int):   0\r\n", string(r.Render()))
+
+	// Provided titles.
+	r, err = New(Options{GroupTitles: []string{"App"}}, nGrps[:1], "")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"App"}, r.GroupTitles())
+
+	// No titles for a single group.
+	r, err = New(Options{}, nGrps[:1], "")
+	assert.Nil(t, err)
+	assert.Empty(t, r.GroupTitles())
+}
+
+func TestRender_DisplaySep(t *testing.T) {
+	c := &struct {
+		Hosts []string `sep:";"`
+	}{Hosts: []string{"a", "b"}}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, c)
+
+	// Default display separator.
+	r, err := New(Options{}, nGrps, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "[a, b]", r.fGrps[0][0].ValueBefore)
+
+	// Custom display separator.
+	r, err = New(Options{DisplaySep: " | "}, nGrps, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "[a | b]", r.fGrps[0][0].ValueBefore)
+}
+
+func TestRender_NameFunc(t *testing.T) {
+	type Server struct {
+		Host string
+	}
+
+	c := &struct {
+		DB struct {
+			Host string
+		}
+		Servers []Server
+	}{Servers: []Server{{Host: "a"}}}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, c)
+
+	nameFunc := func(n *node.Node, heritage []*node.Node) string {
+		name := "svc"
+		for _, hn := range append(heritage, n) {
+			name += "/" + strings.ToUpper(hn.FieldName())
+		}
+		return name
+	}
+
+	r, err := New(Options{NameFunc: nameFunc}, nGrps, "prefix")
+	assert.Nil(t, err)
+	r.Render()
+
+	names := make([]string, 0)
+	for _, f := range r.fGrps[0] {
+		names = append(names, f.Name)
+		for _, itemFg := range f.Items {
+			for _, itemF := range itemFg {
+				names = append(names, itemF.Name)
+			}
+		}
+	}
+	assert.Equal(t, []string{"svc/DB/HOST", "svc/SERVERS", "svc/SERVERS[0].svc/HOST"}, names)
+}
+
+func TestRender_NestedPrefix(t *testing.T) {
+	type Pool struct {
+		Size int
+	}
+
+	type Mid struct {
+		Pool Pool `env:"omitprefix"`
+		Name string
+	}
+
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, &struct {
+		DB Mid `env:"DATABASE"`
+	}{})
+
+	r, err := New(Options{FieldNameFormat: "env"}, nGrps, "")
+	assert.Nil(t, err)
+
+	names := make([]string, 0)
+	for _, f := range r.fGrps[0] {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"DATABASE_SIZE", "DATABASE_NAME"}, names)
+}
+
+func TestRender_FloatFmt(t *testing.T) {
+	c := &struct {
+		Price float64 `fmt:"%.2f"`
+		Rate  float64 `fmt:"%.2f"`
+	}{Rate: 1.5}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, c)
+
+	r, err := New(Options{}, nGrps, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "\r\n"+
+		"Price (float):   0.00\n"+
+		"Rate (float):    1.50 (default: 1.50)\r\n", string(r.Render()))
+}
+
+func TestRender_Width(t *testing.T) {
+	c := &struct {
+		Hosts []string
+	}{Hosts: strings.Split(strings.Repeat("host ", 10), " ")}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, c)
+
+	lines := func(width string) (int, error) {
+		r, err := New(Options{Width: width}, nGrps, "")
+		if err != nil {
+			return 0, err
+		}
+		return strings.Count(strings.TrimSpace(string(r.Render())), "\n"), nil
+	}
+
+	n, err := lines("")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+
+	n, err = lines("60")
+	assert.Nil(t, err)
+	assert.Greater(t, n, 0)
+
+	n, err = lines("0")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+
+	_, err = lines("narrow")
+	assert.EqualError(t, err, "show width: invalid width 'narrow'")
+}
+
+func TestRender_ShowHide(t *testing.T) {
+	type Internal struct {
+		Count int
+	}
+
+	c := &struct {
+		Host     string
+		Password string   `show:"false"`
+		Computed string   `show:"hide"`
+		Internal Internal `show:"hide"`
+	}{Host: "localhost", Password: "secret", Computed: "x", Internal: Internal{Count: 1}}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, c)
+
+	r, err := New(Options{}, nGrps, "")
+	assert.Nil(t, err)
+
+	names := make([]string, 0)
+	for _, f := range r.fGrps[0] {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"Host", "Password"}, names)
+
+	out := string(r.Render())
+	assert.Contains(t, out, node.Redacted)
+	assert.NotContains(t, out, "Computed")
+	assert.NotContains(t, out, "Count")
+}
+
+func TestRender_RelativeTime(t *testing.T) {
+	n := time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return n }
+	defer func() { now = time.Now }()
+
+	c := &struct {
+		Started time.Time
+		Expires time.Time
+		Stopped time.Time
+		Secret  time.Time `show:"false"`
+	}{
+		Started: n.Add(-2*time.Hour - 5*time.Minute),
+		Expires: n.Add(3 * 24 * time.Hour),
+		Secret:  n,
+	}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, c)
+
+	r, err := New(Options{RelativeTime: true}, nGrps, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "2h ago", r.fGrps[0][0].ValueBefore)
+	assert.Equal(t, "in 3d", r.fGrps[0][1].ValueBefore)
+	assert.Equal(t, "", r.fGrps[0][2].ValueBefore)
+	assert.Equal(t, node.Redacted, r.fGrps[0][3].ValueBefore)
+
+	c.Stopped = n.Add(-30 * time.Second)
+	r.Render()
+	assert.Equal(t, "30s ago", r.fGrps[0][2].ValueAfter)
+
+	// The 'fmt' layout is used by default.
+	r, err = New(Options{}, nGrps, "")
+	assert.Nil(t, err)
+	assert.Equal(t, c.Started.Format(time.RFC3339), r.fGrps[0][0].ValueBefore)
+}
+
+func TestRender_SliceTypes(t *testing.T) {
+	c := &struct {
+		Bools     []bool
+		Floats    []float64
+		Durations []time.Duration
+		Empty     []time.Duration
+	}{
+		Bools:     []bool{true, false},
+		Floats:    []float64{1.5, 2},
+		Durations: []time.Duration{time.Second, 2 * time.Minute},
+	}
+	nGrps := node.MakeAllNodes(node.Options{
+		NoFollow: []string{"time.Time"},
+	}, c)
+
+	r, err := New(Options{}, nGrps, "")
+	assert.Nil(t, err)
+
+	fields := r.fGrps[0]
+	assert.Equal(t, "bools", fields[0].Type)
+	assert.Equal(t, "[true, false]", fields[0].ValueBefore)
+	assert.Equal(t, "floats", fields[1].Type)
+	assert.Equal(t, "[1.5, 2]", fields[1].ValueBefore)
+	assert.Equal(t, "durations", fields[2].Type)
+	assert.Equal(t, "[1s, 2m0s]", fields[2].ValueBefore)
+	assert.Equal(t, "durations", fields[3].Type)
+	assert.Equal(t, "[]", fields[3].ValueBefore)
+
+	for _, f := range fields[:3] {
+		assert.False(t, f.IsZero(f.ValueBefore), f.Name)
+	}
+	assert.True(t, fields[3].IsZero(fields[3].ValueBefore))
+
+	c.Bools = nil
+	c.Floats = []float64{}
+	c.Empty = []time.Duration{time.Hour}
+	r.Render()
+	assert.True(t, fields[0].IsZero(fields[0].ValueAfter))
+	assert.True(t, fields[1].IsZero(fields[1].ValueAfter))
+	assert.Equal(t, "[1h0m0s]", fields[3].ValueAfter)
+	assert.False(t, fields[3].IsZero(fields[3].ValueAfter))
+}
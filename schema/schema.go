@@ -0,0 +1,252 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pcelvng/go-config/util"
+	"github.com/pcelvng/go-config/util/node"
+)
+
+var (
+	draft07 = "http://json-schema.org/draft-07/schema#"
+
+	configTag   = "config"
+	ignoreTag   = "ignore"
+	helpTag     = "help"
+	jsonTag     = "json"
+	oneofTag    = "oneof"
+	validateTag = "validate"
+
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// Schema is a (partial) draft-07 JSON Schema.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []interface{}      `json:"enum,omitempty"`
+}
+
+// Generate generates a draft-07 JSON Schema describing the node groups. All node
+// groups are described by a single root object schema.
+//
+// Property names are the 'json' tag name (same as the JSON loader) falling back
+// to the struct field name. Schema keywords come from the following:
+// - "type" from the field type; time.Time is a "date-time" formatted string.
+// - "description" from the 'help' tag.
+// - "required" from the 'req:"true"' (or 'required:"true"') tag.
+// - "enum" from the 'enum:"a,b,c"' tag, the 'oneof:"a b c"' tag or a 'validate:"oneof=a b c"' rule.
+//
+// Ignored fields are not included.
+func Generate(nGrps []*node.Nodes) ([]byte, error) {
+	s, err := New(nGrps)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// New creates the root Schema for the node groups. See "Generate".
+func New(nGrps []*node.Nodes) (*Schema, error) {
+	s := &Schema{
+		Schema:     draft07,
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+
+	for _, nGrp := range nGrps {
+		if err := addProperties(s, nGrp, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// addProperties adds the child nodes of "parent" (by full name) as
+// properties of object schema "s".
+func addProperties(s *Schema, nGrp *node.Nodes, parent string) error {
+	for _, n := range nGrp.List() {
+		if n.ParentName() != parent || isIgnored(n) {
+			continue
+		}
+
+		name, ok := propName(n)
+		if !ok {
+			continue
+		}
+
+		// Embedded structs are flattened (same as encoding/json).
+		if n.Field.Anonymous && n.GetTag(jsonTag) == "" && n.IsStruct() && !n.IsTime() {
+			if err := addProperties(s, nGrp, n.FullName()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		prop, err := nodeSchema(n, nGrp)
+		if err != nil {
+			return err
+		}
+		s.Properties[name] = prop
+
+		if n.IsRequired() {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	return nil
+}
+
+// nodeSchema creates the property schema for node "n".
+func nodeSchema(n *node.Node, nGrp *node.Nodes) (*Schema, error) {
+	name, _ := propName(n)
+	s := &Schema{
+		Description: util.ExpandHelp(n.GetTag(helpTag), name, n.DisplayString()),
+	}
+
+	switch {
+	case n.IsTime():
+		s.Type, s.Format = "string", "date-time"
+	case n.IsStructSlice():
+		itemT := n.FieldValue.Type().Elem()
+		if itemT.Kind() == reflect.Ptr {
+			itemT = itemT.Elem()
+		}
+
+		items, err := New([]*node.Nodes{node.MakeNodes(
+			node.Options{NoFollow: []string{"time.Time"}},
+			reflect.New(itemT).Interface(),
+		)})
+		if err != nil {
+			return nil, err
+		}
+		items.Schema = ""
+
+		s.Type, s.Items = "array", items
+	case n.IsStruct():
+		s.Type = "object"
+		s.Properties = make(map[string]*Schema)
+		if err := addProperties(s, nGrp, n.FullName()); err != nil {
+			return nil, err
+		}
+	case n.IsSlice():
+		itemT := n.FieldValue.Type().Elem()
+		if itemT.Kind() == reflect.Ptr {
+			itemT = itemT.Elem()
+		}
+
+		items := &Schema{}
+		items.Type, items.Format = jsonType(itemT)
+		s.Type, s.Items = "array", items
+	default:
+		s.Type, s.Format = jsonType(n.FieldValue.Type())
+	}
+
+	// Enum values apply to the item type for slices.
+	vals := oneof(n)
+	if len(vals) > 0 {
+		enumS := s
+		if s.Items != nil {
+			enumS = s.Items
+		}
+
+		for _, v := range vals {
+			ev, err := enumValue(enumS.Type, v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid oneof value '%v' for field '%v': %v", v, n.FullName(), err)
+			}
+			enumS.Enum = append(enumS.Enum, ev)
+		}
+	}
+
+	return s, nil
+}
+
+// jsonType returns the JSON Schema type and format (if any) of type "t".
+func jsonType(t reflect.Type) (typ, format string) {
+	if t == timeType {
+		return "string", "date-time"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean", ""
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer", ""
+	case reflect.Float32, reflect.Float64:
+		return "number", ""
+	}
+
+	return "string", ""
+}
+
+// enumValue converts enum value "v" to the JSON Schema type "typ".
+func enumValue(typ, v string) (interface{}, error) {
+	switch typ {
+	case "boolean":
+		return strconv.ParseBool(v)
+	case "integer":
+		return strconv.ParseInt(v, 10, 64)
+	case "number":
+		return strconv.ParseFloat(v, 64)
+	}
+
+	return v, nil
+}
+
+// oneof returns the allowed values from the 'enum' tag (comma separated), the
+// 'oneof' tag or the 'validate' tag "oneof=" rule (space separated).
+func oneof(n *node.Node) []string {
+	if vals := n.EnumValues(); len(vals) > 0 {
+		return vals
+	}
+
+	if v := n.GetTag(oneofTag); v != "" {
+		return strings.Fields(v)
+	}
+
+	for _, rule := range strings.Split(n.GetTag(validateTag), ",") {
+		if strings.HasPrefix(rule, "oneof=") {
+			return strings.Fields(strings.TrimPrefix(rule, "oneof="))
+		}
+	}
+
+	return nil
+}
+
+// propName returns the property name from the 'json' tag falling back to
+// the struct field name. Returns false if the field is excluded with 'json:"-"'.
+func propName(n *node.Node) (string, bool) {
+	name := strings.Split(n.GetTag(jsonTag), ",")[0]
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = n.FieldName()
+	}
+
+	return name, true
+}
+
+// isIgnored checks if the node is ignored.
+//
+// A node is ignored when one or more of the following struct
+// field tag cases are met:
+// - `ignore:"true"`
+// - `config:"ignore"`
+func isIgnored(n *node.Node) bool {
+	return n.GetBoolTag(ignoreTag) || n.GetTag(configTag) == "ignore"
+}
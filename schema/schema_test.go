@@ -0,0 +1,106 @@
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pcelvng/go-config/util/node"
+)
+
+type DB struct {
+	Host string `json:"host" req:"true" help:"Database host."`
+	Port int    `json:"port"`
+}
+
+type Server struct {
+	Name string
+}
+
+type Embedded struct {
+	Region string `oneof:"east west"`
+}
+
+type AppConfig struct {
+	Embedded
+	Level    string    `json:"level" validate:"oneof=debug info"`
+	Retries  int       `json:"retries" oneof:"1 2 3"`
+	Ratio    float64   `json:"ratio"`
+	Format   string    `json:"format" enum:"json, text"`
+	Debug    bool      `json:"debug"`
+	Start    time.Time `json:"start"`
+	Tags     []string  `json:"tags"`
+	DB       DB        `json:"db"`
+	Servers  []Server  `json:"servers"`
+	Internal string    `json:"-"`
+	Ignored  string    `ignore:"true"`
+}
+
+func TestNew(t *testing.T) {
+	nGrps := node.MakeAllNodes(node.Options{NoFollow: []string{"time.Time"}}, &AppConfig{})
+
+	s, err := New(nGrps)
+	assert.Nil(t, err)
+
+	expected := &Schema{
+		Schema: draft07,
+		Type:   "object",
+		Properties: map[string]*Schema{
+			"Region":  {Type: "string", Enum: []interface{}{"east", "west"}},
+			"level":   {Type: "string", Enum: []interface{}{"debug", "info"}},
+			"retries": {Type: "integer", Enum: []interface{}{int64(1), int64(2), int64(3)}},
+			"ratio":   {Type: "number"},
+			"format":  {Type: "string", Enum: []interface{}{"json", "text"}},
+			"debug":   {Type: "boolean"},
+			"start":   {Type: "string", Format: "date-time"},
+			"tags":    {Type: "array", Items: &Schema{Type: "string"}},
+			"db": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"host": {Type: "string", Description: "Database host."},
+					"port": {Type: "integer"},
+				},
+				Required: []string{"host"},
+			},
+			"servers": {
+				Type: "array",
+				Items: &Schema{
+					Type:       "object",
+					Properties: map[string]*Schema{"Name": {Type: "string"}},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, s)
+}
+
+func TestNew_InvalidEnum(t *testing.T) {
+	c := &struct {
+		Port int `oneof:"80 http"`
+	}{}
+
+	_, err := New(node.MakeAllNodes(node.Options{NoFollow: []string{"time.Time"}}, c))
+	assert.EqualError(t, err, `invalid oneof value 'http' for field 'Port': strconv.ParseInt: parsing "http": invalid syntax`)
+}
+
+func TestGenerate(t *testing.T) {
+	c := &struct {
+		Name string `json:"name" req:"true"`
+	}{}
+
+	b, err := Generate(node.MakeAllNodes(node.Options{NoFollow: []string{"time.Time"}}, c))
+	assert.Nil(t, err)
+	assert.Equal(t, `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "name": {
+      "type": "string"
+    }
+  },
+  "required": [
+    "name"
+  ]
+}`, string(b))
+}
@@ -0,0 +1,74 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SplitArgs splits "s" (such as response file contents or a command line) into
+// arguments using shell-like rules:
+//   - arguments are separated by whitespace (including new lines).
+//   - single quoted values are literal. For example, 'a "b" \c'.
+//   - double quoted values support the "\"" and "\\" escapes. For example, "a \"b\"".
+//   - outside of quotes a backslash escapes the next character. For example, a\ b.
+//   - a "#" at the start of an argument starts a comment that runs to the end of the line.
+func SplitArgs(s string) ([]string, error) {
+	args := make([]string, 0)
+	var arg strings.Builder
+	inArg := false // true when an argument is being built (allows empty quoted args).
+
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		case r == '#' && !inArg:
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case r == '\\':
+			if i+1 < len(rs) {
+				i++
+				arg.WriteRune(rs[i])
+			}
+			inArg = true
+		case r == '\'':
+			end := i + 1
+			for end < len(rs) && rs[end] != '\'' {
+				end++
+			}
+			if end == len(rs) {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			arg.WriteString(string(rs[i+1 : end]))
+			i = end
+			inArg = true
+		case r == '"':
+			i++
+			for ; i < len(rs) && rs[i] != '"'; i++ {
+				if rs[i] == '\\' && i+1 < len(rs) && (rs[i+1] == '"' || rs[i+1] == '\\') {
+					i++
+				}
+				arg.WriteRune(rs[i])
+			}
+			if i == len(rs) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inArg = true
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	return args, nil
+}
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jbsmith7741/trial"
+)
+
+func TestSplitArgs(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		return SplitArgs(args[0].(string))
+	}
+	cases := trial.Cases{
+		"whitespace": {
+			Input:    "--host=a\n  --port 80\t-v\r\n",
+			Expected: []string{"--host=a", "--port", "80", "-v"},
+		},
+		"quotes": {
+			Input:    `--name="a b" --msg='say "hi" \n' --path="C:\\dir \"x\""`,
+			Expected: []string{"--name=a b", `--msg=say "hi" \n`, `--path=C:\dir "x"`},
+		},
+		"escapes": {
+			Input:    `a\ b \#c`,
+			Expected: []string{"a b", "#c"},
+		},
+		"comments": {
+			Input:    "# CI flags\n--host=a # the host\n--port=80#not-a-comment\n",
+			Expected: []string{"--host=a", "--port=80#not-a-comment"},
+		},
+		"empty quoted": {
+			Input:    `--name ""`,
+			Expected: []string{"--name", ""},
+		},
+		"unterminated single": {
+			Input:       `'abc`,
+			ExpectedErr: errors.New("unterminated single quote"),
+		},
+		"unterminated double": {
+			Input:       `"abc`,
+			ExpectedErr: errors.New("unterminated double quote"),
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}
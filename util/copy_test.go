@@ -0,0 +1,51 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeepCopy(t *testing.T) {
+	type DB struct {
+		Hosts []string
+	}
+
+	type Config struct {
+		Name    string
+		Start   time.Time
+		DB      *DB
+		Labels  map[string][]string
+		Any     interface{}
+		Ports   [2]int
+		private *DB
+	}
+
+	level := 1
+	orig := &Config{
+		Name:    "app",
+		Start:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		DB:      &DB{Hosts: []string{"a", "b"}},
+		Labels:  map[string][]string{"env": {"prod"}},
+		Any:     &level,
+		Ports:   [2]int{80, 443},
+		private: &DB{},
+	}
+
+	c := DeepCopy(orig).(*Config)
+	assert.Equal(t, orig, c)
+
+	// No shared memory through exported fields.
+	c.DB.Hosts[0] = "z"
+	c.Labels["env"][0] = "dev"
+	*c.Any.(*int) = 2
+	assert.Equal(t, "a", orig.DB.Hosts[0])
+	assert.Equal(t, "prod", orig.Labels["env"][0])
+	assert.Equal(t, 1, level)
+
+	// Unexported fields are shallow copied.
+	assert.True(t, orig.private == c.private)
+
+	assert.Nil(t, DeepCopy(nil))
+}
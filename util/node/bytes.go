@@ -0,0 +1,135 @@
+package node
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// byteUnits contains the supported byte size units ordered from
+// largest to smallest (binary before decimal of the same magnitude).
+var byteUnits = []struct {
+	name string
+	size int64
+}{
+	{"TiB", 1 << 40},
+	{"TB", 1e12},
+	{"GiB", 1 << 30},
+	{"GB", 1e9},
+	{"MiB", 1 << 20},
+	{"MB", 1e6},
+	{"KiB", 1 << 10},
+	{"KB", 1e3},
+	{"B", 1},
+}
+
+// ParseByteSize parses a human readable byte size such as "10MB" or "2GiB"
+// into the number of bytes. Decimal (KB, MB, GB, TB) and binary (KiB, MiB, GiB, TiB)
+// units are supported (case-insensitive). A value without a unit is read as bytes.
+//
+// Fractional values (i.e. "1.5KB") are supported as long as the result
+// is a whole number of bytes.
+func ParseByteSize(s string) (int64, error) {
+	v := strings.TrimSpace(s)
+
+	size := int64(1)
+	for _, u := range byteUnits {
+		if len(v) > len(u.name) && strings.EqualFold(v[len(v)-len(u.name):], u.name) {
+			v = strings.TrimSpace(v[:len(v)-len(u.name)])
+			size = u.size
+			break
+		}
+	}
+
+	if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if i != 0 && (i*size)/i != size {
+			return 0, fmt.Errorf("byte size '%v' is out of range", s)
+		}
+		return i * size, nil
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size '%v'", s)
+	}
+
+	b := f * float64(size)
+	if b != math.Trunc(b) || math.Abs(b) > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid byte size '%v'", s)
+	}
+
+	return int64(b), nil
+}
+
+// FormatByteSize formats "b" bytes as a human readable byte size using the largest
+// unit that represents the value exactly. For example, 10000000 is "10MB" and
+// 2147483648 is "2GiB". Zero is "0".
+func FormatByteSize(b int64) string {
+	if b == 0 {
+		return "0"
+	}
+
+	for _, u := range byteUnits {
+		if b%u.size == 0 {
+			return strconv.FormatInt(b/u.size, 10) + u.name
+		}
+	}
+
+	return strconv.FormatInt(b, 10) + "B"
+}
+
+// isByteSize returns true when the node is an integer field
+// with the 'unit:"bytes"' tag.
+func (n *Node) isByteSize() bool {
+	if n.GetTag("unit") != "bytes" || n.IsDuration() {
+		return false
+	}
+
+	switch n.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
+
+// setByteSize parses the byte size "s" and sets the integer field value.
+func (n *Node) setByteSize(s string) error {
+	b, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+
+	v := n.FieldValue
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if b < 0 || v.OverflowUint(uint64(b)) {
+			return fmt.Errorf("byte size '%v' is out of range for type '%v'", s, v.Type())
+		}
+		v.SetUint(uint64(b))
+	default:
+		if v.OverflowInt(b) {
+			return fmt.Errorf("byte size '%v' is out of range for type '%v'", s, v.Type())
+		}
+		v.SetInt(b)
+	}
+
+	return nil
+}
+
+// byteSizeString formats the integer field value as a human readable byte size.
+func (n *Node) byteSizeString() string {
+	v := n.FieldValue
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return strconv.FormatUint(v.Uint(), 10) + "B"
+		}
+		return FormatByteSize(int64(v.Uint()))
+	}
+
+	return FormatByteSize(v.Int())
+}
@@ -0,0 +1,64 @@
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jbsmith7741/trial"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		return ParseByteSize(args[0].(string))
+	}
+	cases := trial.Cases{
+		"bytes":         {Input: "512", Expected: int64(512)},
+		"bytes unit":    {Input: "512B", Expected: int64(512)},
+		"decimal":       {Input: "10MB", Expected: int64(10000000)},
+		"binary":        {Input: "2GiB", Expected: int64(2147483648)},
+		"lowercase":     {Input: "4kib", Expected: int64(4096)},
+		"space":         {Input: "1 KB", Expected: int64(1000)},
+		"fraction":      {Input: "1.5KiB", Expected: int64(1536)},
+		"partial bytes": {Input: "1.0001KB", ExpectedErr: errors.New("invalid byte size '1.0001KB'")},
+		"invalid":       {Input: "ten MB", ExpectedErr: errors.New("invalid byte size 'ten MB'")},
+		"out of range":  {Input: "9000000TiB", ExpectedErr: errors.New("byte size '9000000TiB' is out of range")},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestFormatByteSize(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		return FormatByteSize(args[0].(int64)), nil
+	}
+	cases := trial.Cases{
+		"zero":    {Input: int64(0), Expected: "0"},
+		"bytes":   {Input: int64(1500), Expected: "1500B"},
+		"decimal": {Input: int64(10000000), Expected: "10MB"},
+		"binary":  {Input: int64(2147483648), Expected: "2GiB"},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestNode_ByteSize(t *testing.T) {
+	c := &struct {
+		MaxSize int64 `unit:"bytes"`
+		Small   uint8 `unit:"bytes"`
+		Count   int
+	}{}
+	nodes := MakeNodes(Options{}, c)
+
+	assert.NoError(t, nodes.Map()["MaxSize"].SetFieldValue("10MB"))
+	assert.Equal(t, int64(10000000), c.MaxSize)
+	assert.Equal(t, "10MB", nodes.Map()["MaxSize"].String())
+
+	err := nodes.Map()["Small"].SetFieldValue("1KB")
+	assert.EqualError(t, err, "field 'Small': byte size '1KB' is out of range for type 'uint8'")
+
+	err = nodes.Map()["MaxSize"].SetFieldValue("lots")
+	assert.EqualError(t, err, "field 'MaxSize': invalid byte size 'lots'")
+
+	// No unit tag.
+	err = nodes.Map()["Count"].SetFieldValue("1KB")
+	assert.Error(t, err)
+}
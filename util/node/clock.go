@@ -0,0 +1,84 @@
+package node
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockFmt is the 'fmt' tag value of time.Duration fields formatted as "HH:MM:SS".
+const clockFmt = "clock"
+
+// ParseClockDuration parses a duration in the "HH:MM:SS" or "MM:SS" clock form
+// (i.e. "01:30:00" or "90:00") with an optional leading "-". The first part can be
+// any number of digits and the other parts must be 00-59. Seconds may have a fraction
+// (i.e. "00:00:01.5").
+func ParseClockDuration(s string) (time.Duration, error) {
+	v := strings.TrimSpace(s)
+	neg := strings.HasPrefix(v, "-")
+	v = strings.TrimPrefix(v, "-")
+
+	parts := strings.Split(v, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid clock duration '%v' expected HH:MM:SS or MM:SS", s)
+	}
+
+	units := []time.Duration{time.Minute, time.Second}
+	if len(parts) == 3 {
+		units = []time.Duration{time.Hour, time.Minute, time.Second}
+	}
+
+	var d time.Duration
+	for i, part := range parts {
+		last := i == len(parts)-1
+		if part == "" || strings.TrimLeft(part, "0123456789.") != "" || (!last && strings.Contains(part, ".")) {
+			return 0, fmt.Errorf("invalid clock duration '%v' expected HH:MM:SS or MM:SS", s)
+		}
+
+		f, err := strconv.ParseFloat(part, 64)
+		if err != nil || (i > 0 && (f >= 60 || len(strings.Split(part, ".")[0]) != 2)) {
+			return 0, fmt.Errorf("invalid clock duration '%v' expected HH:MM:SS or MM:SS", s)
+		}
+
+		d += time.Duration(f * float64(units[i]))
+	}
+
+	if neg {
+		return -d, nil
+	}
+	return d, nil
+}
+
+// FormatClockDuration formats "d" in the "HH:MM:SS" clock form. Hours are at least
+// two digits and fractional seconds are included when not zero. For example, 90 minutes
+// is "01:30:00" and 1.5 seconds is "00:00:01.5".
+func FormatClockDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	sec := (d % time.Minute) / time.Second
+	frac := d % time.Second
+
+	s := fmt.Sprintf("%s%02d:%02d:%02d", sign, h, m, sec)
+	if frac > 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%09d", frac), "0")
+	}
+
+	return s
+}
+
+// ParseDuration parses "s" with time.ParseDuration. Strings with a ":" are
+// read in the clock form instead (see ParseClockDuration).
+func ParseDuration(s string) (time.Duration, error) {
+	if strings.Contains(s, ":") {
+		return ParseClockDuration(s)
+	}
+
+	return time.ParseDuration(s)
+}
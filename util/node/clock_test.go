@@ -0,0 +1,66 @@
+package node
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jbsmith7741/trial"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseClockDuration(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		return ParseClockDuration(args[0].(string))
+	}
+	cases := trial.Cases{
+		"hh:mm:ss":      {Input: "01:30:00", Expected: 90 * time.Minute},
+		"mm:ss":         {Input: "90:15", Expected: 90*time.Minute + 15*time.Second},
+		"long hours":    {Input: "100:00:01", Expected: 100*time.Hour + time.Second},
+		"fraction":      {Input: "00:00:01.5", Expected: 1500 * time.Millisecond},
+		"negative":      {Input: "-00:01:00", Expected: -time.Minute},
+		"minutes range": {Input: "01:60:00", ExpectedErr: errors.New("invalid clock duration '01:60:00' expected HH:MM:SS or MM:SS")},
+		"one digit":     {Input: "01:5:00", ExpectedErr: errors.New("invalid clock duration '01:5:00' expected HH:MM:SS or MM:SS")},
+		"empty part":    {Input: "01::00", ExpectedErr: errors.New("invalid clock duration '01::00' expected HH:MM:SS or MM:SS")},
+		"too many":      {Input: "1:00:00:00", ExpectedErr: errors.New("invalid clock duration '1:00:00:00' expected HH:MM:SS or MM:SS")},
+		"not a number":  {Input: "aa:00", ExpectedErr: errors.New("invalid clock duration 'aa:00' expected HH:MM:SS or MM:SS")},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestFormatClockDuration(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		return FormatClockDuration(args[0].(time.Duration)), nil
+	}
+	cases := trial.Cases{
+		"zero":     {Input: time.Duration(0), Expected: "00:00:00"},
+		"hours":    {Input: 90 * time.Minute, Expected: "01:30:00"},
+		"fraction": {Input: 1500 * time.Millisecond, Expected: "00:00:01.5"},
+		"negative": {Input: -time.Minute, Expected: "-00:01:00"},
+		"days":     {Input: 100 * time.Hour, Expected: "100:00:00"},
+	}
+	trial.New(fn, cases).Test(t)
+}
+
+func TestNode_ClockDuration(t *testing.T) {
+	c := &struct {
+		Window  time.Duration   `fmt:"clock"`
+		Windows []time.Duration `fmt:"clock"`
+		Timeout time.Duration
+	}{}
+	nodes := MakeNodes(Options{}, c)
+
+	assert.NoError(t, nodes.Map()["Window"].SetFieldValue("01:30:00"))
+	assert.Equal(t, 90*time.Minute, c.Window)
+	assert.Equal(t, "01:30:00", nodes.Map()["Window"].String())
+
+	assert.NoError(t, nodes.Map()["Windows"].SetSlice([]string{"00:01", "1m30s"}))
+	assert.Equal(t, []string{"00:00:01", "00:01:30"}, nodes.Map()["Windows"].SliceString())
+
+	// The standard form is used without the 'fmt:"clock"' tag.
+	assert.NoError(t, nodes.Map()["Timeout"].SetFieldValue("00:01:30"))
+	assert.Equal(t, "1m30s", nodes.Map()["Timeout"].String())
+
+	err := nodes.Map()["Timeout"].SetFieldValue("1:3O")
+	assert.EqualError(t, err, "field 'Timeout': invalid clock duration '1:3O' expected HH:MM:SS or MM:SS")
+}
@@ -0,0 +1,62 @@
+package node
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/pcelvng/go-config/util"
+)
+
+// MergeNonZero copies each non-zero field value of "src" to the field of the same
+// full name (see "Node.FullName") in "dst". Nested structs and pointers are followed
+// and slices are copied so that "dst" and "src" do not share the underlying array.
+//
+// "dst" must be a struct pointer. "src" may be a struct or struct pointer and is not
+// modified. Fields of "src" not found in "dst" are skipped.
+//
+// An error naming the field is returned if the "dst" and "src" field types don't match.
+func MergeNonZero(dst, src interface{}) error {
+	sv := reflect.Indirect(reflect.ValueOf(src))
+	if sv.Kind() != reflect.Struct {
+		return fmt.Errorf("src '%v' must be a struct or struct pointer", reflect.TypeOf(src))
+	}
+
+	// Deep copy src so nil pointers (including nested pointers shared with src)
+	// initialized when generating the node tree are not set on the provided value.
+	sCopy := reflect.New(sv.Type())
+	sCopy.Elem().Set(reflect.ValueOf(util.DeepCopy(sv.Interface())))
+
+	o := Options{NoFollow: []string{"time.Time"}, NoInitNil: true}
+	dNodes := MakeNodes(o, dst)
+	sNodes := MakeNodes(o, sCopy.Interface()).Map()
+
+	for _, n := range dNodes.List() {
+		// Struct fields are set through their child fields.
+		if n.IsStruct() && !n.IsTime() {
+			continue
+		}
+
+		sn, ok := sNodes[n.FullName()]
+		if !ok || sn.FieldValue.IsZero() {
+			continue
+		}
+
+		if sn.FieldValue.Type() != n.FieldValue.Type() {
+			return fmt.Errorf("field '%v' type '%v' does not match type '%v'",
+				n.FullName(), sn.ValueType(), n.ValueType())
+		}
+
+		if n.IsSlice() {
+			slice := reflect.MakeSlice(sn.FieldValue.Type(), 0, sn.FieldValue.Len())
+			n.FieldValue.Set(reflect.AppendSlice(slice, sn.FieldValue))
+			continue
+		}
+
+		n.FieldValue.Set(sn.FieldValue)
+	}
+
+	// Struct pointers in dst that didn't receive a value are left nil.
+	dNodes.PruneNil()
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeNonZero(t *testing.T) {
+	type DB struct {
+		Host string
+		Port int
+	}
+
+	type Config struct {
+		Name  string
+		Hosts []string
+		Start time.Time
+		DB    DB
+		Cache *DB
+		Level *int
+	}
+
+	level := 3
+	dst := &Config{
+		Name:  "base",
+		Hosts: []string{"a"},
+		DB:    DB{Host: "localhost", Port: 5432},
+	}
+	src := &Config{
+		Hosts: []string{"b", "c"},
+		Start: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		DB:    DB{Port: 6432},
+		Level: &level,
+	}
+
+	err := MergeNonZero(dst, src)
+	assert.NoError(t, err)
+	assert.Equal(t, &Config{
+		Name:  "base",
+		Hosts: []string{"b", "c"},
+		Start: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		DB:    DB{Host: "localhost", Port: 6432},
+		Level: &level,
+	}, dst)
+
+	// Slices are not shared.
+	src.Hosts[0] = "z"
+	assert.Equal(t, "b", dst.Hosts[0])
+
+	// src is not modified.
+	assert.Nil(t, src.Cache)
+}
+
+func TestMergeNonZero_NestedPointers(t *testing.T) {
+	type inner struct{ Z string }
+	type mid struct {
+		Y  string
+		In *inner
+	}
+	type cfg struct {
+		M *mid
+	}
+
+	dst := &cfg{}
+	src := &cfg{M: &mid{Y: "y"}}
+
+	assert.NoError(t, MergeNonZero(dst, src))
+	assert.Equal(t, "y", dst.M.Y)
+	assert.Nil(t, dst.M.In)
+
+	// Nested nil pointers of src are not initialized.
+	assert.Nil(t, src.M.In)
+}
+
+func TestMergeNonZero_TypeMismatch(t *testing.T) {
+	dst := &struct {
+		DB struct{ Port int }
+	}{}
+	src := struct {
+		DB struct{ Port string }
+	}{DB: struct{ Port string }{Port: "5432"}}
+
+	err := MergeNonZero(dst, src)
+	assert.EqualError(t, err, "field 'DB.Port' type 'string' does not match type 'int'")
+
+	err = MergeNonZero(dst, "src")
+	assert.EqualError(t, err, "src 'string' must be a struct or struct pointer")
+}
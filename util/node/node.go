@@ -1,6 +1,7 @@
 package node
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
@@ -34,6 +35,47 @@ func (ns *Nodes) StructPtr() interface{} {
 	return ns.v
 }
 
+// PruneNil sets struct pointers that were nil before generating the node tree
+// back to nil when the struct value is still the zero value. That is, when none
+// of the struct fields received a value.
+//
+// Only applies when the nodes were generated with Options.NoInitNil. Nested
+// struct pointers are pruned before their parents.
+func (ns *Nodes) PruneNil() {
+	for i := len(ns.nodesSlice) - 1; i >= 0; i-- {
+		n := ns.nodesSlice[i]
+		if !n.nilPtr.IsValid() {
+			continue
+		}
+
+		if n.FieldValue.IsZero() {
+			n.nilPtr.Set(reflect.Zero(n.nilPtr.Type()))
+		}
+	}
+}
+
+// Copy returns the nodes of a deep copy (see util.DeepCopy) of the underlying struct
+// generated with "o". The runtime tag overrides and meta of each node are copied so
+// the copy can be changed (for example, redacted) without modifying the original struct.
+func (ns *Nodes) Copy(o Options) *Nodes {
+	c := MakeNodes(o, util.DeepCopy(ns.v))
+	for name, cn := range c.nodesMap {
+		n, ok := ns.nodesMap[name]
+		if !ok {
+			continue
+		}
+
+		for k, v := range n.tag {
+			cn.tag[k] = v
+		}
+		for k, v := range n.meta {
+			cn.meta[k] = v
+		}
+	}
+
+	return c
+}
+
 // SetTag will attempt to set fieldName Node tag with key and value.
 // An error is returned if the node is not found.
 //
@@ -65,7 +107,8 @@ type Node struct {
 	// Index is the field index in the struct. AKA the field's "order" relative to other
 	// fields in the struct. Note that since private fields are skipped the Index value
 	// can also skip. For example, you may end up with fields in the same struct with
-	// Index values {1,2,4,5} because '3' is a private member.
+	// Index values {1,2,4,5} because '3' is a private member. Private fields with a
+	// setter method are not skipped (see "privateNode").
 	Index int
 
 	// tag holds tag overrides set at runtime.
@@ -79,6 +122,17 @@ type Node struct {
 	// meta provides allowance for pre- or post-processing metadata
 	// for sharing information such as a resolved variable name.
 	meta map[string]string
+
+	// nilPtr is the original (nil) struct pointer field value. Only set when
+	// Options.NoInitNil is true and the struct pointer was initialized
+	// by MakeNodes. See "Nodes.PruneNil".
+	nilPtr reflect.Value
+
+	// setter sets the value of a private field through a setter method
+	// on the struct. "FieldValue" is then a copy of the private field value
+	// that is synced after each set. See "privateSetter".
+	setter  func(string) error
+	private reflect.Value
 }
 
 // FieldName is offered for convenience in getting the
@@ -145,6 +199,69 @@ func (n *Node) SetTag(key, value string) {
 	n.tag[key] = value
 }
 
+// Tags returns all the field struct tags as a map of key to value with
+// any runtime overrides (see SetTag) applied.
+func (n *Node) Tags() map[string]string {
+	tags := parseStructTag(n.Field.Tag)
+	for k, v := range n.tag {
+		tags[k] = v
+	}
+
+	return tags
+}
+
+// parseStructTag reads the conventional 'key:"value"' pairs of "tag" into a map
+// following the same rules as "reflect.StructTag.Lookup".
+func parseStructTag(tag reflect.StructTag) map[string]string {
+	tags := make(map[string]string)
+	for tag != "" {
+		// Skip leading space.
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// Scan to colon.
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		name := string(tag[:i])
+		tag = tag[i+1:]
+
+		// Scan quoted string to find value.
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		qvalue := string(tag[:i+1])
+		tag = tag[i+1:]
+
+		value, err := strconv.Unquote(qvalue)
+		if err != nil {
+			break
+		}
+		if _, ok := tags[name]; !ok {
+			tags[name] = value
+		}
+	}
+
+	return tags
+}
+
 // GetTag has the same behavior as "reflect.StructTag.Get"
 // but checks first if the value exists as a runtime override first.
 func (n *Node) GetTag(key string) string {
@@ -221,6 +338,31 @@ func (n *Node) IsSlice() bool {
 	return n.Kind() == reflect.Slice
 }
 
+// IsStructSlice returns true when the node represents a slice of structs
+// (or a slice of struct pointers).
+func (n *Node) IsStructSlice() bool {
+	if n.Kind() != reflect.Slice {
+		return false
+	}
+
+	baseType := n.FieldValue.Type().Elem()
+	if baseType.Kind() == reflect.Ptr {
+		baseType = baseType.Elem()
+	}
+
+	return baseType.Kind() == reflect.Struct
+}
+
+// IsFileOnly returns true when the node value can only be expressed
+// by file based loaders (toml, yaml, json) that natively decode into
+// the underlying struct. Loaders such as env and flag that set values
+// node by node should skip file only nodes.
+//
+// Currently only slices of structs are file only.
+func (n *Node) IsFileOnly() bool {
+	return n.IsStructSlice()
+}
+
 func (n *Node) IsStringSlice() bool {
 	if n.Kind() == reflect.Slice {
 		baseType := reflect.TypeOf(n.FieldValue.Interface()).Elem()
@@ -271,8 +413,231 @@ func fieldString(value reflect.Value) string {
 //
 // The string representation is such that the value could then be set
 // again with one of the appropriate "Set" methods.
+//
+// Integer fields with the 'unit:"bytes"' tag are formatted as a human
+// readable byte size (see FormatByteSize), float fields with a 'fmt'
+// tag (i.e. 'fmt:"%.2f"') are formatted with the provided format, bool
+// fields with the 'fmt:"yesno"' or 'fmt:"onoff"' tag are "yes"/"no" or "on"/"off"
+// and time.Duration fields with the 'fmt:"clock"' tag are "HH:MM:SS" (see FormatClockDuration).
 func (n *Node) String() string {
-	return fieldString(n.FieldValue)
+	if n.isByteSize() {
+		return n.byteSizeString()
+	}
+
+	return n.formatValue(n.FieldValue)
+}
+
+// formatValue returns the string representation of "value" (the field value or a
+// field slice item value). Float values are formatted with the 'fmt' tag format
+// (i.e. 'fmt:"%.2f"'), bool values with the 'fmt' tag bool format (i.e. 'fmt:"yesno"')
+// and time.Duration values with the 'fmt:"clock"' tag in the clock form when provided.
+func (n *Node) formatValue(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if fmtV := n.GetTag("fmt"); strings.Contains(fmtV, "%") {
+			return fmt.Sprintf(fmtV, value.Float())
+		}
+	case reflect.Int64:
+		if value.Type().String() == "time.Duration" && n.GetTag("fmt") == clockFmt {
+			return FormatClockDuration(value.Interface().(time.Duration))
+		}
+	case reflect.Bool:
+		if bf, ok := boolFormats[strings.ToLower(n.GetTag("fmt"))]; ok {
+			if value.Bool() {
+				return bf[0]
+			}
+			return bf[1]
+		}
+	}
+
+	return fieldString(value)
+}
+
+// boolFormats are the 'fmt' tag bool formats as the true and false strings.
+// Both are accepted by ParseBool.
+var boolFormats = map[string][2]string{
+	"yesno": {"yes", "no"},
+	"onoff": {"on", "off"},
+}
+
+// Redacted is the display string of values that are not shown.
+const Redacted = "[redacted]"
+
+// ValueString returns a generic string representation of any node value
+// including the special cases:
+// - time.Time values are formatted with the 'fmt' tag format (time.RFC3339 by default).
+// - slices are joined with the 'sep' tag separator ("," by default) and wrapped with "[]".
+// - slices with the 'sep' tag ",json" option are a JSON array of strings.
+// - slices of structs are represented by the number of items (i.e. "2 items").
+//
+// Struct values (other than time.Time) return an empty string.
+func (n *Node) ValueString() string {
+	switch {
+	case n.IsTime():
+		return n.TimeString(n.GetTag("fmt"))
+	case n.IsStructSlice():
+		return fmt.Sprintf("%d items", n.FieldValue.Len())
+	case n.IsSliceJSON():
+		b, _ := json.Marshal(n.SliceString())
+		return string(b)
+	case n.IsSlice():
+		sep := n.SliceSep()
+		if sep == "" {
+			sep = ","
+		}
+		return `[` + strings.Join(n.SliceString(), sep) + `]`
+	case n.IsStruct():
+		return ""
+	}
+
+	return n.String()
+}
+
+// IsHidden returns true when the node has the 'show:"hide"' tag. Hidden
+// fields are loaded but left out of the Show output entirely.
+func (n *Node) IsHidden() bool {
+	return n.GetTag("show") == "hide"
+}
+
+// IsShown returns the value of the 'show' tag. If the 'show'
+// tag is not present then defaults to true unless the node is a secret
+// without a 'mask' tag (see IsSecret). A 'show:"hide"' node is not shown.
+func (n *Node) IsShown() bool {
+	if n.GetTag("show") == "" {
+		return !n.IsSecret() || n.GetTag("mask") != ""
+	}
+
+	return n.GetBoolTag("show")
+}
+
+// IsSecret returns true when the node has the 'secret:"true"' tag. A secret is:
+//   - not shown (as if it had the 'show:"false"' tag) unless it has a 'mask' tag,
+//     in which case the value is displayed masked.
+//   - redacted from the resolved config template.
+//   - redacted from the errors returned when setting the value.
+func (n *Node) IsSecret() bool {
+	return n.GetBoolTag("secret")
+}
+
+// ReqTag returns the 'req' tag value falling back to the 'required' tag value
+// when 'req' is not set. The tags are synonyms.
+func (n *Node) ReqTag() string {
+	if v := n.GetTag("req"); v != "" {
+		return v
+	}
+	return n.GetTag("required")
+}
+
+// IsRequired returns true when the 'req' (or 'required') tag is a true value accepted
+// by ParseBool (such as "true", "yes", "on" or "1"). Conditional 'req' tags (such
+// as 'req:"TLSEnabled==true"') are not true values.
+func (n *Node) IsRequired() bool {
+	b, _ := ParseBool(n.ReqTag())
+	return b
+}
+
+// EnumValues returns the allowed values of the comma separated 'enum' tag. For example,
+// 'enum:"debug,info,warn"'. Values are trimmed of surrounding spaces. Returns nil when
+// the tag is not provided.
+func (n *Node) EnumValues() []string {
+	enumV := n.GetTag("enum")
+	if enumV == "" {
+		return nil
+	}
+
+	vals := strings.Split(enumV, ",")
+	for i := range vals {
+		vals[i] = strings.TrimSpace(vals[i])
+	}
+
+	return vals
+}
+
+// EnumString returns the allowed 'enum' tag values for display as "(one of: a|b|c)".
+// Returns an empty string when the tag is not provided.
+func (n *Node) EnumString() string {
+	vals := n.EnumValues()
+	if len(vals) == 0 {
+		return ""
+	}
+
+	return "(one of: " + strings.Join(vals, "|") + ")"
+}
+
+// RedactErr returns "err" with the raw values "vals" replaced by "[redacted]" in
+// the error message when the node is a secret. Otherwise "err" is returned as-is.
+func (n *Node) RedactErr(err error, vals ...string) error {
+	if err == nil || !n.IsSecret() {
+		return err
+	}
+
+	return &redactedErr{err: err, vals: vals}
+}
+
+// redactedErr is an error with raw secret values redacted from the message.
+type redactedErr struct {
+	err  error
+	vals []string
+}
+
+func (e *redactedErr) Error() string {
+	msg := e.err.Error()
+	for _, v := range e.vals {
+		if v != "" {
+			msg = strings.ReplaceAll(msg, v, Redacted)
+		}
+	}
+
+	return msg
+}
+
+func (e *redactedErr) Unwrap() error {
+	return e.err
+}
+
+// DisplayString returns the value string (see ValueString) for display purposes
+// honoring the following tags:
+//   - 'show:"false"' returns "[redacted]".
+//   - 'mask:"{n}"' replaces all but the last "n" characters with "*". For example,
+//     'mask:"4"' displays "secret-1234" as "*******1234". Any other value masks all characters.
+func (n *Node) DisplayString() string {
+	if !n.IsShown() {
+		return Redacted
+	}
+
+	return n.maskString(n.ValueString())
+}
+
+// DisplayStringSep is the same as DisplayString except non-struct slice values
+// are joined with "sep" instead of the 'sep' tag value. Useful when the display
+// separator should differ from the separator used to parse values.
+func (n *Node) DisplayStringSep(sep string) string {
+	if !n.IsShown() {
+		return Redacted
+	}
+
+	if n.IsSlice() && !n.IsStructSlice() {
+		return n.maskString(`[` + strings.Join(n.SliceString(), sep) + `]`)
+	}
+
+	return n.maskString(n.ValueString())
+}
+
+// maskString masks "val" according to the 'mask' tag. A 'mask' value that is not a
+// non-negative number (reported by config.Check) masks the whole value.
+func (n *Node) maskString(val string) string {
+	maskV := n.GetTag("mask")
+	if maskV == "" || val == "" {
+		return val
+	}
+
+	visible, _ := strconv.Atoi(maskV)
+	runes := []rune(val)
+	if visible < 0 || visible > len(runes) {
+		visible = 0
+	}
+
+	return strings.Repeat("*", len(runes)-visible) + string(runes[len(runes)-visible:])
 }
 
 // SliceString performs the same action as String but for slices, returning
@@ -299,7 +664,7 @@ func (n *Node) SliceString() []string {
 			itemValue = itemValue.Elem()
 		}
 
-		items = append(items, fieldString(itemValue))
+		items = append(items, n.formatValue(itemValue))
 	}
 
 	return items
@@ -330,13 +695,69 @@ func (n *Node) TimeString(timeFmt string) string {
 		return ""
 	}
 
+	// Format in the 'tz' tag location (if provided) for symmetry with SetTime.
+	if loc, err := n.location(); err == nil && loc != nil {
+		tv = tv.In(loc)
+	}
+
 	return tv.Format(timeFmt)
 }
 
+// location returns the time location named by the 'tz' tag value. Returns
+// nil if the 'tz' tag is not provided.
+func (n *Node) location() (*time.Location, error) {
+	tz := n.GetTag("tz")
+	if tz == "" {
+		return nil, nil
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'tz' value '%v' for field '%v': %w", tz, n.FullName(), err)
+	}
+
+	return loc, nil
+}
+
+// ItemNodes generates a *Nodes for each item of a struct slice node in
+// slice order. Nil struct pointer items are returned as nil.
+//
+// ItemNodes panics if the node is not a struct slice.
+func (n *Node) ItemNodes(o Options) []*Nodes {
+	if !n.IsStructSlice() {
+		panic(fmt.Sprintf("field value '%v' must be a struct slice and instead was '%v'",
+			n.FullName(),
+			n.ValueType()),
+		)
+	}
+
+	items := make([]*Nodes, 0, n.FieldValue.Len())
+	for i := 0; i < n.FieldValue.Len(); i++ {
+		itemValue := n.FieldValue.Index(i)
+		if itemValue.Kind() == reflect.Ptr {
+			if itemValue.IsNil() {
+				items = append(items, nil)
+				continue
+			}
+			itemValue = itemValue.Elem()
+		}
+
+		items = append(items, makeNodes("", itemValue.Addr().Interface(), o))
+	}
+
+	return items
+}
+
 // SetFieldValue attempts to convert the field value "fv" represented
 // as a string and assign it to the node value. An error is returned
 // if the string cannot be converted to the underlying go type.
 //
+// Integer fields with the 'unit:"bytes"' tag accept human readable byte sizes
+// such as "10MB" (see ParseByteSize).
+//
+// Field types implementing ConfigDecoder are decoded by their ConfigDecode
+// method before any built-in handling.
+//
 // Panics if the node is a pointer, slice or struct.
 func (n *Node) SetFieldValue(s string) error {
 	// Panics if called on pointer, slice or struct.
@@ -351,7 +772,51 @@ func (n *Node) SetFieldValue(s string) error {
 		panic(fmt.Sprintf("node '%s' type is a struct - call SetStruct method instead", n.FullName()))
 	}
 
-	return setField(n.FieldValue, s)
+	// Private fields are set with the struct setter method.
+	if n.setter != nil {
+		if err := n.setter(s); err != nil {
+			return fmt.Errorf("field '%v': %w", n.FullName(), n.RedactErr(err, s))
+		}
+		n.syncPrivate()
+		return nil
+	}
+
+	// Byte sizes such as "10MB" (see ParseByteSize).
+	if _, ok := decoder(n.FieldValue); !ok && n.isByteSize() {
+		if err := n.setByteSize(s); err != nil {
+			return fmt.Errorf("field '%v': %w", n.FullName(), n.RedactErr(err, s))
+		}
+		return nil
+	}
+
+	if err := setField(n.FieldValue, s, n.Tags()); err != nil {
+		return fmt.Errorf("field '%v': %w", n.FullName(), n.RedactErr(err, s))
+	}
+
+	return nil
+}
+
+// CheckValue returns the error setting "s" as the node value (or as a slice item for
+// slices) would return without changing the field value. Struct (other than time.Time)
+// and slice of struct fields always return an error.
+func (n *Node) CheckValue(s string) error {
+	if n.IsStructSlice() || (n.IsStruct() && !n.IsTime()) {
+		return fmt.Errorf("field '%v' cannot be set from a single value", n.FullName())
+	}
+
+	c := *n
+	c.FieldValue = reflect.New(n.FieldValue.Type()).Elem()
+	c.setter = nil
+
+	switch {
+	case c.IsTime():
+		_, err := c.SetTime(s, c.GetTag("fmt"))
+		return err
+	case c.IsSlice():
+		return c.SetSlice([]string{s})
+	}
+
+	return c.SetFieldValue(s)
 }
 
 // SetSlice attempts to convert slice values "vals" to the underlying field
@@ -363,6 +828,86 @@ func (n *Node) SetFieldValue(s string) error {
 // other information about separation such as if the starting and terminating "[]"
 // should be removed.
 func (n *Node) SetSlice(vals []string) error {
+	return n.setSlice(vals, false)
+}
+
+const (
+	// sliceAppendOpt is the 'sep' tag option to append loaded slice elements.
+	sliceAppendOpt = ",append"
+
+	// sliceJSONOpt is the 'sep' tag option to read slice values as a JSON array.
+	sliceJSONOpt = ",json"
+)
+
+// SetSliceAppend behaves like SetSlice only the "vals" elements are appended
+// to the existing slice elements instead of replacing them.
+func (n *Node) SetSliceAppend(vals []string) error {
+	return n.setSlice(vals, true)
+}
+
+// SetSliceJSON sets (or appends with the ",append" option) the slice elements
+// from the JSON array "s". String elements are unquoted and all other elements
+// are read as their JSON text. For example, '["a,b", "c"]' or '[1, 2]'.
+func (n *Node) SetSliceJSON(s string) error {
+	raws := make([]json.RawMessage, 0)
+	if err := json.Unmarshal([]byte(s), &raws); err != nil {
+		return fmt.Errorf("invalid json array: %w", err)
+	}
+
+	vals := make([]string, len(raws))
+	for i, raw := range raws {
+		vals[i] = string(raw)
+		if len(raw) > 0 && raw[0] == '"' {
+			if err := json.Unmarshal(raw, &vals[i]); err != nil {
+				return fmt.Errorf("invalid json array: %w", err)
+			}
+		}
+	}
+
+	return n.setSlice(vals, n.IsSliceAppend())
+}
+
+// SliceSep returns the 'sep' tag slice separator without the ",append" and ",json"
+// options. An empty string is returned if no separator is provided.
+func (n *Node) SliceSep() string {
+	sep, _, _ := n.sliceSepOpts()
+	return sep
+}
+
+// IsSliceAppend returns true when the 'sep' tag has the ",append" option. For
+// example, 'sep:",append"' or 'sep:";,append"'. Loaded slice elements
+// are appended to the existing (default) elements instead of replacing them.
+func (n *Node) IsSliceAppend() bool {
+	_, appnd, _ := n.sliceSepOpts()
+	return n.IsSlice() && appnd
+}
+
+// IsSliceJSON returns true when the 'sep' tag has the ",json" option. For
+// example, 'sep:",json"' or 'sep:",json,append"'. Loaded slice values are
+// read as a JSON array (see SetSliceJSON) instead of being split on the separator.
+func (n *Node) IsSliceJSON() bool {
+	_, _, jsn := n.sliceSepOpts()
+	return n.IsSlice() && !n.IsStructSlice() && jsn
+}
+
+// sliceSepOpts returns the 'sep' tag separator and if the ",append" and ",json"
+// options are present. Options may be provided in any order.
+func (n *Node) sliceSepOpts() (sep string, appnd, jsn bool) {
+	sep = n.GetTag("sep")
+	for {
+		switch {
+		case strings.HasSuffix(sep, sliceAppendOpt):
+			sep, appnd = strings.TrimSuffix(sep, sliceAppendOpt), true
+		case strings.HasSuffix(sep, sliceJSONOpt):
+			sep, jsn = strings.TrimSuffix(sep, sliceJSONOpt), true
+		default:
+			return sep, appnd, jsn
+		}
+	}
+}
+
+// setSlice sets or appends (when "appnd" is true) the slice elements.
+func (n *Node) setSlice(vals []string, appnd bool) error {
 	// Must be a slice.
 	if !n.IsSlice() {
 		panic(fmt.Sprintf("field value '%v' must be a slice and instead was '%v'",
@@ -377,12 +922,16 @@ func (n *Node) SetSlice(vals []string) error {
 	baseType := reflect.TypeOf(fValue.Interface()).Elem()
 
 	slice := reflect.MakeSlice(fValue.Type(), 0, len(vals))
+	if appnd && !fValue.IsNil() {
+		slice = reflect.AppendSlice(slice, fValue)
+	}
+	tags := n.Tags()
 	for _, v := range vals {
 		// Each item must be the correct type.
 		baseValue := reflect.New(baseType).Elem()
-		err := setField(baseValue, v)
+		err := setField(baseValue, v, tags)
 		if err != nil {
-			return err
+			return n.RedactErr(err, v)
 		}
 		slice = reflect.Append(slice, baseValue)
 	}
@@ -392,30 +941,57 @@ func (n *Node) SetSlice(vals []string) error {
 	return nil
 }
 
+// ConfigDecoder is implemented by field types that decode their own raw string value.
+// ConfigDecode is called with the raw value and all the field struct tags (with any
+// runtime overrides applied) so a type can change how it decodes based on a
+// companion tag such as 'fmt'. It is checked before any built-in handling and is
+// usually implemented on the pointer receiver. Slice elements are decoded
+// individually with the slice field tags.
+type ConfigDecoder interface {
+	ConfigDecode(raw string, tags map[string]string) error
+}
+
+// decoder returns the ConfigDecoder implemented by value or its address.
+func decoder(value reflect.Value) (ConfigDecoder, bool) {
+	if value.CanAddr() {
+		if d, ok := value.Addr().Interface().(ConfigDecoder); ok {
+			return d, true
+		}
+	}
+	if value.Kind() != reflect.Ptr && value.CanInterface() {
+		d, ok := value.Interface().(ConfigDecoder)
+		return d, ok
+	}
+
+	return nil, false
+}
+
 // setField converts the string s to the type of value and sets the value if possible.
-// Pointers and slices are recursively dealt with by following the pointer
-// or creating a generic slice of type value.
-func setField(value reflect.Value, s string) error {
+// Types implementing ConfigDecoder decode the value themselves with "tags". Integers
+// are parsed with the bit size of the value type so out of range values (i.e. "300"
+// for an int8) return an error. time.Duration values are read with time.ParseDuration
+// or in the "HH:MM:SS" clock form. Pointers and slices are recursively dealt with by
+// following the pointer or creating a generic slice of type value.
+func setField(value reflect.Value, s string, tags map[string]string) error {
+	if d, ok := decoder(value); ok {
+		return d.ConfigDecode(s, tags)
+	}
+
 	switch value.Kind() {
 	case reflect.String:
 		value.SetString(s)
 	case reflect.Bool:
-		switch strings.ToLower(s) {
-		case "true":
-			value.SetBool(true)
-		case "false", "":
-			value.SetBool(false)
-		default:
-			// the bool value should be explicit to tell user
-			// something is amiss.
-			return fmt.Errorf("cannot assign '%v' to bool type", s)
+		b, err := ParseBool(s)
+		if err != nil {
+			return err
 		}
+		value.SetBool(b)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		// check for time.Duration int64 special case.
 		//
 		// TODO: check if this still works when time package is vendored or there is a way to fake this.
 		if value.Type().String() == "time.Duration" {
-			d, err := time.ParseDuration(s)
+			d, err := ParseDuration(s)
 			if err != nil {
 				return err
 			}
@@ -424,14 +1000,20 @@ func setField(value reflect.Value, s string) error {
 			return nil
 		}
 
-		i, err := strconv.ParseInt(s, 10, 0)
+		i, err := strconv.ParseInt(s, 10, value.Type().Bits())
+		if errors.Is(err, strconv.ErrRange) {
+			return fmt.Errorf("value '%v' is out of range for type '%v'", s, value.Type())
+		}
 		if err != nil {
 			return err
 		}
 
 		value.SetInt(i)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		i, err := strconv.ParseUint(s, 10, 0)
+		i, err := strconv.ParseUint(s, 10, value.Type().Bits())
+		if errors.Is(err, strconv.ErrRange) {
+			return fmt.Errorf("value '%v' is out of range for type '%v'", s, value.Type())
+		}
 		if err != nil {
 			return err
 		}
@@ -446,7 +1028,7 @@ func setField(value reflect.Value, s string) error {
 	case reflect.Ptr:
 		// Create non-pointer type and recursively assign.
 		z := reflect.New(value.Type().Elem())
-		err := setField(z.Elem(), s)
+		err := setField(z.Elem(), s, tags)
 		if err != nil {
 			return err
 		}
@@ -459,6 +1041,23 @@ func setField(value reflect.Value, s string) error {
 	return nil
 }
 
+// ParseBool parses "s" as a bool. Accepted values (case-insensitive) are:
+// - true: "true", "t", "yes", "on", "1"
+// - false: "false", "f", "no", "off", "0", ""
+//
+// Any other value returns an error so that a misconfigured value is not
+// silently read as false.
+func ParseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "t", "yes", "on", "1":
+		return true, nil
+	case "false", "f", "no", "off", "0", "":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("cannot assign '%v' to bool type", s)
+}
+
 // SetStruct will attempt to assign "v" as the underlying node field value.
 // panics if "v" is not a struct.
 func (n *Node) SetStruct(v interface{}) {
@@ -517,6 +1116,10 @@ func NormTimeFormat(timeFmt string) string {
 // value.
 //
 // Default timeFmt format is time.RFC3339.
+//
+// If the node has a 'tz' tag (i.e. 'tz:"America/Denver"') then time values
+// without a time zone offset are interpreted in that location. Otherwise
+// such values are interpreted as UTC.
 func (n *Node) SetTime(tv, timeFmt string) (usedFmt string, err error) {
 	timeFmt = NormTimeFormat(timeFmt)
 
@@ -524,11 +1127,21 @@ func (n *Node) SetTime(tv, timeFmt string) (usedFmt string, err error) {
 		return timeFmt, errors.New("cannot set value because it is not of type time.Time")
 	}
 
-	t, err := time.Parse(timeFmt, tv)
+	loc, err := n.location()
 	if err != nil {
 		return timeFmt, err
 	}
 
+	var t time.Time
+	if loc != nil {
+		t, err = time.ParseInLocation(timeFmt, tv, loc)
+	} else {
+		t, err = time.Parse(timeFmt, tv)
+	}
+	if err != nil {
+		return timeFmt, n.RedactErr(err, tv)
+	}
+
 	n.SetStruct(t)
 	return timeFmt, nil
 }
@@ -567,9 +1180,14 @@ func MakeAllNodes(o Options, vs ...interface{}) []*Nodes {
 // - interface
 // - maps
 //
+// Slices of structs are represented as a single node without following
+// the struct fields. See "ItemNodes" for generating nodes for each item after
+// the slice values are loaded.
+//
 // Note that any nil pointers will get initialized. Therefore, using "MakeNodes"
 // has the side effect of fully initializing the provided struct and all its
-// sub-parts (except private members which are skipped).
+// sub-parts (except private members which are skipped). See Options.NoInitNil
+// for setting unused struct pointers back to nil after loading.
 //
 // "time.Time" is NOT included by default in the Options.NoFollow list.
 //
@@ -595,6 +1213,14 @@ type Options struct {
 	// are always ignored. This list allows the user to define a list of types
 	// such as a custom "int" type.
 	IgnoreTypes []string
+
+	// NoInitNil tracks nil struct pointers that are initialized when generating
+	// the node tree so they can be set back to nil with "Nodes.PruneNil" when no
+	// value was loaded into them.
+	//
+	// Struct pointers still need to be initialized in order to discover the
+	// underlying struct fields.
+	NoInitNil bool
 }
 
 // makeNodes iterates and recurses through the provided struct pointer.
@@ -612,17 +1238,25 @@ func makeNodes(prefix string, v interface{}, options Options) (nodes *Nodes) {
 	for i := 0; i < vStruct.NumField(); i++ {
 		rawField := vStruct.Field(i)
 
-		if !rawField.CanSet() { // skip private variables
+		if !rawField.CanSet() { // private variables
+			if n := privateNode(prefix, vStruct, i); n != nil {
+				addNode(nodes, n)
+			}
 			continue
 		}
 
 		// Check if field is pointer and follow to get the actual
 		// value. If the pointer is nil then initialize.
 		field := rawField
+		var nilPtr reflect.Value
 		if rawField.Kind() == reflect.Ptr {
 			if rawField.IsNil() {
 				z := reflect.New(rawField.Type().Elem())
 				rawField.Set(z)
+
+				if options.NoInitNil && z.Elem().Kind() == reflect.Struct {
+					nilPtr = rawField
+				}
 			}
 
 			// Follow pointer.
@@ -639,13 +1273,22 @@ func makeNodes(prefix string, v interface{}, options Options) (nodes *Nodes) {
 			continue
 		}
 
-		// Skip slice if underlying type is not a basic type.
+		// Skip slice if underlying type is not a basic type or a struct.
+		//
+		// Slices of structs are kept as a single (file only) node. The struct
+		// fields are not followed since the number of items is not known
+		// until the values are loaded.
 		if field.Kind() == reflect.Slice {
 			baseType := reflect.TypeOf(field.Interface()).Elem()
 			if baseType.Kind() == reflect.Ptr {
 				baseType = baseType.Elem()
 			}
-			if !isBasicType(baseType.Kind()) {
+			if baseType.Kind() == reflect.Struct {
+				if !followStruct(baseType.String(), options.NoFollow) ||
+					isIgnoredType(baseType.String(), options.IgnoreTypes) {
+					continue
+				}
+			} else if !isBasicType(baseType.Kind()) {
 				continue
 			}
 		}
@@ -657,6 +1300,7 @@ func makeNodes(prefix string, v interface{}, options Options) (nodes *Nodes) {
 			Index:      i,
 			tag:        make(map[string]string),
 			meta:       make(map[string]string),
+			nilPtr:     nilPtr,
 		}
 
 		addNode(nodes, node)
@@ -669,6 +1313,91 @@ func makeNodes(prefix string, v interface{}, options Options) (nodes *Nodes) {
 	return nodes
 }
 
+// privateNode returns a node for the private basic type field at index "i" of
+// "vStruct" when the struct has a setter method for it. Nil is returned otherwise.
+//
+// The node "FieldValue" is a settable copy of the private field value so the
+// value can be read like any other field.
+func privateNode(prefix string, vStruct reflect.Value, i int) *Node {
+	rawField := vStruct.Field(i)
+	if !isBasicType(rawField.Kind()) {
+		return nil
+	}
+
+	sf := vStruct.Type().Field(i)
+	setter := privateSetter(vStruct, sf.Name)
+	if setter == nil {
+		return nil
+	}
+
+	n := &Node{
+		Prefix:     prefix,
+		FieldValue: reflect.New(rawField.Type()).Elem(),
+		Field:      sf,
+		Index:      i,
+		tag:        make(map[string]string),
+		meta:       make(map[string]string),
+		setter:     setter,
+		private:    rawField,
+	}
+	n.syncPrivate()
+
+	return n
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// privateSetter returns a func calling the "Set<Name>(string) error" method
+// or the "SetConfigField(name, value string) error" method of the addressable
+// struct "vStruct". Nil is returned if neither method exists.
+func privateSetter(vStruct reflect.Value, name string) func(string) error {
+	if !vStruct.CanAddr() {
+		return nil
+	}
+	strType := reflect.TypeOf("")
+	ptr := vStruct.Addr()
+
+	call := func(m reflect.Value, args ...reflect.Value) error {
+		err, _ := m.Call(args)[0].Interface().(error)
+		return err
+	}
+
+	m := ptr.MethodByName("Set" + strings.ToUpper(name[:1]) + name[1:])
+	if m.IsValid() && m.Type() == reflect.FuncOf([]reflect.Type{strType}, []reflect.Type{errorType}, false) {
+		return func(s string) error {
+			return call(m, reflect.ValueOf(s))
+		}
+	}
+
+	m = ptr.MethodByName("SetConfigField")
+	if m.IsValid() && m.Type() == reflect.FuncOf([]reflect.Type{strType, strType}, []reflect.Type{errorType}, false) {
+		return func(s string) error {
+			return call(m, reflect.ValueOf(name), reflect.ValueOf(s))
+		}
+	}
+
+	return nil
+}
+
+// syncPrivate copies the private field value to the node "FieldValue".
+// Reading basic kinds from a private field is allowed by reflect even
+// though it cannot be set or converted to an interface.
+func (n *Node) syncPrivate() {
+	v := n.private
+	switch v.Kind() {
+	case reflect.String:
+		n.FieldValue.SetString(v.String())
+	case reflect.Bool:
+		n.FieldValue.SetBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n.FieldValue.SetInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n.FieldValue.SetUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n.FieldValue.SetFloat(v.Float())
+	}
+}
+
 func addNode(nodes *Nodes, n *Node) *Nodes {
 	nodes.nodesMap[n.FullName()] = n
 	nodes.nodesSlice = append(nodes.nodesSlice, n)
@@ -790,7 +1519,9 @@ func Parents(child *Node, nodes map[string]*Node) (parents []*Node) {
 // for node n taking into account it's node heritage from heritage.
 type FieldString func(n *Node, heritage []*Node) (name string)
 
-// ValueType returns a simple string representation of the type.
+// ValueType returns a simple string representation of the type. Slice
+// types are the element type followed by "s". For example, "bools",
+// "floats" or "durations".
 func ValueType(n *Node) string {
 	if n.IsTime() {
 		return "time"
@@ -804,11 +1535,21 @@ func ValueType(n *Node) string {
 		return n.ValueType()
 	}
 
+	if n.IsStructSlice() {
+		return "structs"
+	}
+
 	kind := n.FieldValue.Kind()
 	suffix := ""
 	if n.IsSlice() {
 		suffix = "s"
 		baseType := reflect.TypeOf(n.FieldValue.Interface()).Elem()
+		if baseType.Kind() == reflect.Ptr {
+			baseType = baseType.Elem()
+		}
+		if baseType.String() == "time.Duration" {
+			return "durations"
+		}
 		kind = baseType.Kind()
 	}
 
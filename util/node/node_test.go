@@ -1,6 +1,8 @@
 package node
 
 import (
+	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
@@ -355,3 +357,539 @@ func TestStructNodes(t *testing.T) {
 	assert.Equal(t, "2020-01-01T15:04:05Z", nodes["Time"].TimeString(""))    // default time: "2006-01-02T15:04:05Z07:00"
 	assert.Equal(t, "2020-01-01T15:04:05Z", nodes["TimePtr"].TimeString("")) // default time: "2006-01-02T15:04:05Z07:00")
 }
+
+func TestStructSliceNodes(t *testing.T) {
+	type Server struct {
+		Host string
+		Port int
+	}
+
+	type SliceStruct struct {
+		Servers    []Server
+		ServerPtrs []*Server
+		Times      []time.Time // not followed - skipped.
+	}
+
+	ss := &SliceStruct{}
+	nodes := MakeNodes(Options{
+		NoFollow: []string{"time.Time"},
+	}, ss).Map()
+
+	assert.Equal(t, 2, len(nodes))
+	assert.True(t, nodes["Servers"].IsStructSlice())
+	assert.True(t, nodes["Servers"].IsFileOnly())
+	assert.True(t, nodes["ServerPtrs"].IsStructSlice())
+	assert.Equal(t, "structs", ValueType(nodes["Servers"]))
+
+	// Item nodes are generated from the loaded values.
+	ss.Servers = []Server{{Host: "a", Port: 1}, {Host: "b", Port: 2}}
+	ss.ServerPtrs = []*Server{nil, {Host: "c"}}
+
+	items := nodes["Servers"].ItemNodes(Options{})
+	assert.Equal(t, 2, len(items))
+	assert.Equal(t, "b", items[1].Map()["Host"].String())
+	assert.Nil(t, items[1].Map()["Port"].SetFieldValue("3"))
+	assert.Equal(t, 3, ss.Servers[1].Port)
+
+	items = nodes["ServerPtrs"].ItemNodes(Options{})
+	assert.Equal(t, 2, len(items))
+	assert.Nil(t, items[0])
+	assert.Equal(t, "c", items[1].Map()["Host"].String())
+
+	assert.Panics(t, func() { MakeNodes(Options{}, &struct{ S string }{}).Map()["S"].ItemNodes(Options{}) })
+}
+
+func TestNodes_PruneNil(t *testing.T) {
+	type DB struct {
+		Host string
+	}
+
+	type Parent struct {
+		Child *DB
+	}
+
+	type PruneStruct struct {
+		DB      *DB
+		UsedDB  *DB
+		Parent  *Parent
+		Default *DB
+		Str     *string // not a struct pointer - not pruned.
+	}
+
+	ps := &PruneStruct{Default: &DB{}}
+	nodes := MakeNodes(Options{NoInitNil: true}, ps)
+	assert.NotNil(t, ps.DB)
+
+	assert.Nil(t, nodes.Map()["UsedDB.Host"].SetFieldValue("localhost"))
+	nodes.PruneNil()
+
+	assert.Nil(t, ps.DB)
+	assert.Nil(t, ps.Parent)
+	assert.Equal(t, &DB{Host: "localhost"}, ps.UsedDB)
+	assert.NotNil(t, ps.Default) // was not nil to begin with.
+	assert.NotNil(t, ps.Str)
+
+	// Without NoInitNil pointers remain initialized.
+	ps = &PruneStruct{}
+	MakeNodes(Options{}, ps).PruneNil()
+	assert.NotNil(t, ps.DB)
+}
+
+func TestNode_DisplayString(t *testing.T) {
+	type DisplayStruct struct {
+		String   string
+		Hidden   string `show:"false"`
+		Masked   string `mask:"4"`
+		MaskAll  string `mask:"true"`
+		Slice    []int  `sep:";"`
+		Time     time.Time
+		Duration time.Duration
+	}
+
+	ds := &DisplayStruct{
+		String:   "value",
+		Hidden:   "secret",
+		Masked:   "secret-1234",
+		MaskAll:  "secret",
+		Slice:    []int{1, 2},
+		Time:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Duration: time.Second,
+	}
+	nodes := MakeNodes(Options{NoFollow: []string{"time.Time"}}, ds).Map()
+
+	assert.Equal(t, "value", nodes["String"].DisplayString())
+	assert.Equal(t, Redacted, nodes["Hidden"].DisplayString())
+	assert.Equal(t, "*******1234", nodes["Masked"].DisplayString())
+	assert.Equal(t, "******", nodes["MaskAll"].DisplayString())
+	assert.Equal(t, "[1;2]", nodes["Slice"].DisplayString())
+	assert.Equal(t, "2020-01-01T00:00:00Z", nodes["Time"].DisplayString())
+	assert.Equal(t, "1s", nodes["Duration"].DisplayString())
+}
+
+func TestNode_SetTime_TZ(t *testing.T) {
+	type TZStruct struct {
+		Local   time.Time `fmt:"2006-01-02 15:04:05" tz:"America/Denver"`
+		UTC     time.Time `fmt:"2006-01-02 15:04:05"`
+		Invalid time.Time `tz:"Not/AZone"`
+	}
+
+	tzs := &TZStruct{}
+	nodes := MakeNodes(Options{NoFollow: []string{"time.Time"}}, tzs).Map()
+
+	denver, err := time.LoadLocation("America/Denver")
+	assert.Nil(t, err)
+
+	_, err = nodes["Local"].SetTime("2020-01-01 10:00:00", "2006-01-02 15:04:05")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2020, 1, 1, 10, 0, 0, 0, denver).Unix(), tzs.Local.Unix())
+	assert.Equal(t, "2020-01-01 10:00:00", nodes["Local"].TimeString("2006-01-02 15:04:05"))
+
+	_, err = nodes["UTC"].SetTime("2020-01-01 10:00:00", "2006-01-02 15:04:05")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC), tzs.UTC)
+
+	_, err = nodes["Invalid"].SetTime("2020-01-01T10:00:00Z", "")
+	assert.Error(t, err)
+}
+
+func TestNode_SetFieldValue_Bool(t *testing.T) {
+	c := &struct {
+		Enabled bool
+		Ptr     *bool
+	}{}
+	nodes := MakeNodes(Options{}, c)
+
+	for _, v := range []string{"true", "T", "yes", "ON", "1"} {
+		c.Enabled = false
+		assert.NoError(t, nodes.Map()["Enabled"].SetFieldValue(v), v)
+		assert.True(t, c.Enabled, v)
+	}
+
+	for _, v := range []string{"false", "f", "No", "off", "0", ""} {
+		c.Enabled = true
+		assert.NoError(t, nodes.Map()["Enabled"].SetFieldValue(v), v)
+		assert.False(t, c.Enabled, v)
+	}
+
+	assert.NoError(t, nodes.Map()["Ptr"].SetFieldValue("yes"))
+	assert.True(t, *c.Ptr)
+
+	err := nodes.Map()["Enabled"].SetFieldValue("maybe")
+	assert.EqualError(t, err, "field 'Enabled': cannot assign 'maybe' to bool type")
+}
+
+func TestNode_SetFieldValue_IntRange(t *testing.T) {
+	c := &struct {
+		I8  int8
+		I16 int16
+		I32 int32
+		I64 int64
+		U8  uint8
+		U16 uint16
+		U32 uint32
+		U64 uint64
+		Ptr *int8
+	}{}
+	nodes := MakeNodes(Options{}, c)
+
+	cases := []struct {
+		field    string
+		min, max string
+		under    string
+		over     string
+		typ      string
+	}{
+		{"I8", "-128", "127", "-129", "128", "int8"},
+		{"I16", "-32768", "32767", "-32769", "32768", "int16"},
+		{"I32", "-2147483648", "2147483647", "-2147483649", "2147483648", "int32"},
+		{"I64", "-9223372036854775808", "9223372036854775807", "-9223372036854775809", "9223372036854775808", "int64"},
+		{"U8", "0", "255", "", "256", "uint8"},
+		{"U16", "0", "65535", "", "65536", "uint16"},
+		{"U32", "0", "4294967295", "", "4294967296", "uint32"},
+		{"U64", "0", "18446744073709551615", "", "18446744073709551616", "uint64"},
+	}
+	for _, tc := range cases {
+		n := nodes.Map()[tc.field]
+		assert.NoError(t, n.SetFieldValue(tc.min), tc.field)
+		assert.Equal(t, tc.min, n.String(), tc.field)
+		assert.NoError(t, n.SetFieldValue(tc.max), tc.field)
+		assert.Equal(t, tc.max, n.String(), tc.field)
+
+		err := n.SetFieldValue(tc.over)
+		assert.EqualError(t, err, fmt.Sprintf("field '%v': value '%v' is out of range for type '%v'", tc.field, tc.over, tc.typ))
+		if tc.under != "" {
+			err = n.SetFieldValue(tc.under)
+			assert.EqualError(t, err, fmt.Sprintf("field '%v': value '%v' is out of range for type '%v'", tc.field, tc.under, tc.typ))
+		}
+	}
+
+	err := nodes.Map()["Ptr"].SetFieldValue("300")
+	assert.EqualError(t, err, "field 'Ptr': value '300' is out of range for type 'int8'")
+}
+
+// baseInt decodes itself as hex when the field has the 'fmt:"hex"' tag.
+type baseInt int
+
+func (b *baseInt) ConfigDecode(raw string, tags map[string]string) error {
+	base := 10
+	if tags["fmt"] == "hex" {
+		base = 16
+	}
+
+	i, err := strconv.ParseInt(raw, base, 64)
+	if err != nil {
+		return fmt.Errorf("invalid base %v value '%v'", base, raw)
+	}
+	*b = baseInt(i)
+	return nil
+}
+
+func TestNode_CheckValue(t *testing.T) {
+	c := &struct {
+		Port  int
+		Size  int64 `unit:"bytes"`
+		Ports []uint8
+		Start time.Time `fmt:"2006-01-02"`
+		DB    struct{ Host string }
+	}{Port: 80}
+	nodes := MakeNodes(Options{NoFollow: []string{"time.Time"}}, c)
+
+	assert.NoError(t, nodes.Map()["Port"].CheckValue("443"))
+	assert.EqualError(t, nodes.Map()["Port"].CheckValue("abc"), `field 'Port': strconv.ParseInt: parsing "abc": invalid syntax`)
+	assert.NoError(t, nodes.Map()["Size"].CheckValue("10MB"))
+	assert.NoError(t, nodes.Map()["Ports"].CheckValue("80"))
+	assert.Error(t, nodes.Map()["Ports"].CheckValue("256"))
+	assert.NoError(t, nodes.Map()["Start"].CheckValue("2024-01-02"))
+	assert.Error(t, nodes.Map()["Start"].CheckValue("yesterday"))
+	assert.EqualError(t, nodes.Map()["DB"].CheckValue("x"), "field 'DB' cannot be set from a single value")
+
+	// The field values are not changed.
+	assert.Equal(t, 80, c.Port)
+	assert.Nil(t, c.Ports)
+}
+
+func TestNode_SetFieldValue_ConfigDecoder(t *testing.T) {
+	c := &struct {
+		Hex   baseInt   `fmt:"hex"`
+		Dec   baseInt   `unit:"bytes"`
+		Ptr   *baseInt  `fmt:"hex"`
+		Hexes []baseInt `fmt:"hex"`
+	}{}
+	nodes := MakeNodes(Options{}, c)
+
+	assert.NoError(t, nodes.Map()["Hex"].SetFieldValue("ff"))
+	assert.Equal(t, baseInt(255), c.Hex)
+
+	// ConfigDecode is checked before the byte size handling.
+	assert.NoError(t, nodes.Map()["Dec"].SetFieldValue("10"))
+	assert.Equal(t, baseInt(10), c.Dec)
+
+	assert.NoError(t, nodes.Map()["Ptr"].SetFieldValue("10"))
+	assert.Equal(t, baseInt(16), *c.Ptr)
+
+	assert.NoError(t, nodes.Map()["Hexes"].SetSlice([]string{"a", "b"}))
+	assert.Equal(t, []baseInt{10, 11}, c.Hexes)
+
+	// Runtime tag overrides are passed to ConfigDecode.
+	nodes.Map()["Hex"].SetTag("fmt", "dec")
+	assert.NoError(t, nodes.Map()["Hex"].SetFieldValue("10"))
+	assert.Equal(t, baseInt(10), c.Hex)
+
+	err := nodes.Map()["Dec"].SetFieldValue("ff")
+	assert.EqualError(t, err, "field 'Dec': invalid base 10 value 'ff'")
+}
+
+func TestNode_Tags(t *testing.T) {
+	c := &struct {
+		Name string `env:"NAME" help:"the \"name\"" fmt:"x"`
+	}{}
+	n := MakeNodes(Options{}, c).Map()["Name"]
+	n.SetTag("fmt", "y")
+
+	assert.Equal(t, map[string]string{"env": "NAME", "help": `the "name"`, "fmt": "y"}, n.Tags())
+}
+
+func TestNode_IsSecret(t *testing.T) {
+	c := &struct {
+		Key    string `secret:"true"`
+		Token  string `secret:"true" mask:"2"`
+		Port   int    `secret:"true"`
+		Ports  []int  `secret:"true"`
+		Shown  string `secret:"true" show:"true"`
+		Public string
+	}{Key: "abc", Token: "abcd", Shown: "x"}
+	nodes := MakeNodes(Options{}, c).Map()
+
+	assert.True(t, nodes["Key"].IsSecret())
+	assert.False(t, nodes["Public"].IsSecret())
+	assert.Equal(t, Redacted, nodes["Key"].DisplayString())
+	assert.Equal(t, "**cd", nodes["Token"].DisplayString())
+	assert.Equal(t, "x", nodes["Shown"].DisplayString())
+
+	// Secret values are redacted from set value errors.
+	err := nodes["Port"].SetFieldValue("hunter2")
+	assert.EqualError(t, err, `field 'Port': strconv.ParseInt: parsing "[redacted]": invalid syntax`)
+	err = nodes["Ports"].SetSlice([]string{"1", "hunter2"})
+	assert.EqualError(t, err, `strconv.ParseInt: parsing "[redacted]": invalid syntax`)
+	err = MakeNodes(Options{}, &struct{ Port int }{}).Map()["Port"].SetFieldValue("hunter2")
+	assert.EqualError(t, err, `field 'Port': strconv.ParseInt: parsing "hunter2": invalid syntax`)
+}
+
+func TestNode_IsRequired(t *testing.T) {
+	c := &struct {
+		Req      string `req:"true"`
+		Required string `required:"yes"`
+		On       string `required:"ON"`
+		One      string `req:"1"`
+		Both     string `req:"false" required:"true"`
+		Cond     string `required:"Req==a"`
+		None     string
+	}{}
+	nodes := MakeNodes(Options{}, c).Map()
+
+	for _, name := range []string{"Req", "Required", "On", "One"} {
+		assert.True(t, nodes[name].IsRequired(), name)
+	}
+	for _, name := range []string{"Both", "Cond", "None"} {
+		assert.False(t, nodes[name].IsRequired(), name)
+	}
+	assert.Equal(t, "Req==a", nodes["Cond"].ReqTag())
+
+	// Runtime tag overrides.
+	nodes["None"].SetTag("required", "true")
+	assert.True(t, nodes["None"].IsRequired())
+}
+
+type privateCfg struct {
+	host    string
+	port    int
+	debug   bool
+	skipped string
+	Public  string
+}
+
+func (c *privateCfg) SetHost(s string) error {
+	c.host = s
+	return nil
+}
+
+func (c *privateCfg) SetPort(s string) error {
+	p, err := strconv.Atoi(s)
+	if err != nil || p <= 0 {
+		return fmt.Errorf("invalid port '%v'", s)
+	}
+	c.port = p
+	return nil
+}
+
+type fieldSetterCfg struct {
+	level string
+}
+
+func (c *fieldSetterCfg) SetConfigField(name, value string) error {
+	if name != "level" {
+		return fmt.Errorf("unknown field '%v'", name)
+	}
+	c.level = value
+	return nil
+}
+
+func TestNode_PrivateSetter(t *testing.T) {
+	c := &privateCfg{host: "localhost", port: 80}
+	nodes := MakeNodes(Options{}, c)
+
+	// Only private fields with a setter method have nodes.
+	assert.Len(t, nodes.List(), 3)
+	assert.Nil(t, nodes.Map()["debug"])
+	assert.Nil(t, nodes.Map()["skipped"])
+	assert.Equal(t, "localhost", nodes.Map()["host"].String())
+	assert.Equal(t, "80", nodes.Map()["port"].String())
+
+	assert.NoError(t, nodes.Map()["host"].SetFieldValue("example.com"))
+	assert.NoError(t, nodes.Map()["port"].SetFieldValue("8080"))
+	assert.Equal(t, "example.com", c.host)
+	assert.Equal(t, 8080, c.port)
+	assert.Equal(t, "8080", nodes.Map()["port"].String())
+
+	err := nodes.Map()["port"].SetFieldValue("-1")
+	assert.EqualError(t, err, "field 'port': invalid port '-1'")
+	assert.Equal(t, 8080, c.port)
+
+	fc := &fieldSetterCfg{}
+	n := MakeNodes(Options{}, fc).Map()["level"]
+	assert.NoError(t, n.SetFieldValue("debug"))
+	assert.Equal(t, "debug", fc.level)
+}
+
+func TestNode_String_FloatFmt(t *testing.T) {
+	c := &struct {
+		Price  float64   `fmt:"%.2f"`
+		Ratio  float32   `fmt:"%.3f"`
+		Prices []float64 `fmt:"%.2f"`
+		Plain  float64
+	}{Price: 1.5, Ratio: 0.25, Prices: []float64{100, 2.5}, Plain: 1.50}
+	nodes := MakeNodes(Options{}, c)
+
+	assert.Equal(t, "1.50", nodes.Map()["Price"].String())
+	assert.Equal(t, "0.250", nodes.Map()["Ratio"].String())
+	assert.Equal(t, []string{"100.00", "2.50"}, nodes.Map()["Prices"].SliceString())
+	assert.Equal(t, "[100.00,2.50]", nodes.Map()["Prices"].DisplayString())
+	assert.Equal(t, "1.5", nodes.Map()["Plain"].String())
+
+	// Parsing is unaffected.
+	assert.NoError(t, nodes.Map()["Price"].SetFieldValue("3.14159"))
+	assert.Equal(t, 3.14159, c.Price)
+}
+
+func TestNode_String_BoolFmt(t *testing.T) {
+	c := &struct {
+		Debug   bool   `fmt:"yesno"`
+		Cache   bool   `fmt:"onoff"`
+		Enabled []bool `fmt:"yesno"`
+		Plain   bool
+	}{Debug: true, Enabled: []bool{true, false}}
+	nodes := MakeNodes(Options{}, c)
+
+	assert.Equal(t, "yes", nodes.Map()["Debug"].String())
+	assert.Equal(t, "off", nodes.Map()["Cache"].String())
+	assert.Equal(t, []string{"yes", "no"}, nodes.Map()["Enabled"].SliceString())
+	assert.Equal(t, "false", nodes.Map()["Plain"].String())
+
+	// Formatted values parse back to the same value.
+	assert.NoError(t, nodes.Map()["Cache"].SetFieldValue(nodes.Map()["Cache"].String()))
+	assert.False(t, c.Cache)
+}
+
+func TestNode_SetSliceAppend(t *testing.T) {
+	c := &struct {
+		Hosts   []string `sep:",append"`
+		Ports   []int    `sep:";,append"`
+		Names   []string `sep:";"`
+		Allowed []string `sep:",append"`
+	}{Hosts: []string{"a"}, Ports: []int{1}}
+	nodes := MakeNodes(Options{}, c)
+
+	assert.True(t, nodes.Map()["Hosts"].IsSliceAppend())
+	assert.True(t, nodes.Map()["Ports"].IsSliceAppend())
+	assert.False(t, nodes.Map()["Names"].IsSliceAppend())
+	assert.Equal(t, "", nodes.Map()["Hosts"].SliceSep())
+	assert.Equal(t, ";", nodes.Map()["Ports"].SliceSep())
+	assert.Equal(t, ";", nodes.Map()["Names"].SliceSep())
+
+	assert.NoError(t, nodes.Map()["Hosts"].SetSliceAppend([]string{"b", "c"}))
+	assert.Equal(t, []string{"a", "b", "c"}, c.Hosts)
+
+	// Each append compounds.
+	assert.NoError(t, nodes.Map()["Ports"].SetSliceAppend([]string{"2"}))
+	assert.NoError(t, nodes.Map()["Ports"].SetSliceAppend([]string{"3"}))
+	assert.Equal(t, []int{1, 2, 3}, c.Ports)
+	assert.Equal(t, "[1;2;3]", nodes.Map()["Ports"].ValueString())
+
+	// Nil slice.
+	assert.NoError(t, nodes.Map()["Allowed"].SetSliceAppend([]string{"x"}))
+	assert.Equal(t, []string{"x"}, c.Allowed)
+
+	// SetSlice still replaces.
+	assert.NoError(t, nodes.Map()["Hosts"].SetSlice([]string{"d"}))
+	assert.Equal(t, []string{"d"}, c.Hosts)
+}
+
+func TestNode_SetSliceJSON(t *testing.T) {
+	c := &struct {
+		Names []string        `sep:",json"`
+		Ports []int           `sep:",json,append"`
+		Waits []time.Duration `sep:";,append,json"`
+		Hosts []string
+	}{Ports: []int{1}}
+	nodes := MakeNodes(Options{}, c)
+
+	assert.True(t, nodes.Map()["Names"].IsSliceJSON())
+	assert.False(t, nodes.Map()["Names"].IsSliceAppend())
+	assert.True(t, nodes.Map()["Ports"].IsSliceJSON())
+	assert.True(t, nodes.Map()["Ports"].IsSliceAppend())
+	assert.True(t, nodes.Map()["Waits"].IsSliceJSON())
+	assert.True(t, nodes.Map()["Waits"].IsSliceAppend())
+	assert.Equal(t, ";", nodes.Map()["Waits"].SliceSep())
+	assert.False(t, nodes.Map()["Hosts"].IsSliceJSON())
+
+	// Quoted elements may contain the separator.
+	assert.NoError(t, nodes.Map()["Names"].SetSliceJSON(`["a,b", "c\"d"]`))
+	assert.Equal(t, []string{"a,b", `c"d`}, c.Names)
+	assert.Equal(t, `["a,b","c\"d"]`, nodes.Map()["Names"].ValueString())
+
+	// Non string elements and the append option.
+	assert.NoError(t, nodes.Map()["Ports"].SetSliceJSON(`[2, "3"]`))
+	assert.Equal(t, []int{1, 2, 3}, c.Ports)
+
+	assert.NoError(t, nodes.Map()["Waits"].SetSliceJSON(`["1s"]`))
+	assert.Equal(t, []time.Duration{time.Second}, c.Waits)
+
+	err := nodes.Map()["Names"].SetSliceJSON(`a,b`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid json array")
+}
+
+func TestNodes_Copy(t *testing.T) {
+	type db struct {
+		Host string
+	}
+	c := &struct {
+		Name  string
+		Hosts []string
+		DB    *db
+	}{Name: "a", Hosts: []string{"x"}, DB: &db{Host: "h"}}
+	nodes := MakeNodes(Options{}, c)
+	nodes.Map()["Name"].SetTag("show", "false")
+	nodes.Map()["Name"].SetMeta("source", "env")
+
+	cp := nodes.Copy(Options{})
+	assert.Equal(t, "false", cp.Map()["Name"].GetTag("show"))
+	assert.Equal(t, "env", cp.Map()["Name"].GetMeta("source"))
+
+	assert.NoError(t, cp.Map()["Name"].SetFieldValue("b"))
+	assert.NoError(t, cp.Map()["DB.Host"].SetFieldValue("z"))
+	cp.Map()["Hosts"].FieldValue.Index(0).SetString("y")
+	assert.Equal(t, "a", c.Name)
+	assert.Equal(t, "h", c.DB.Host)
+	assert.Equal(t, []string{"x"}, c.Hosts)
+}
@@ -0,0 +1,106 @@
+package node
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FindPath returns the node of the dot "." separated field path "pth". Numeric path
+// segments are slice indices so individual slice items can be referenced. For example,
+// "Servers.0.Host" is the "Host" field of the first "Servers" struct slice item and
+// "Tags.1" is the second "Tags" item. Field names are matched case-insensitively
+// when "fold" is true. Struct slice item nodes are generated with "o".
+//
+// A nil node and nil error are returned when no field matches. An error is returned
+// for an out of range index, indexing a non-slice field or a nil struct pointer item.
+func (ns *Nodes) FindPath(pth string, fold bool, o Options) (*Node, error) {
+	return ns.findPath(strings.Split(pth, "."), 1, fold, o)
+}
+
+// findPath finds the node of the path segments "segs" checking for slice
+// index segments from "start".
+func (ns *Nodes) findPath(segs []string, start int, fold bool, o Options) (*Node, error) {
+	for i := start; i < len(segs); i++ {
+		idx, err := strconv.Atoi(segs[i])
+		if err != nil {
+			continue
+		}
+
+		n := ns.find(strings.Join(segs[:i], "."), fold)
+		if n == nil {
+			return nil, nil
+		}
+
+		item, err := n.item(idx)
+		if err != nil {
+			return nil, err
+		}
+		if i == len(segs)-1 {
+			return item, nil
+		}
+
+		if !n.IsStructSlice() {
+			return nil, fmt.Errorf("field '%v' is not a struct and has no field '%v'", item.FullName(), segs[i+1])
+		}
+
+		// Remaining segments are fields of the struct item.
+		itemNodes := makeNodes(item.FullName(), item.FieldValue.Addr().Interface(), o)
+		return itemNodes.findPath(segs, i+2, fold, o)
+	}
+
+	return ns.find(strings.Join(segs, "."), fold), nil
+}
+
+// find returns the node with the full name "fullName" or nil if there is no match.
+func (ns *Nodes) find(fullName string, fold bool) *Node {
+	if !fold {
+		return ns.nodesMap[fullName]
+	}
+
+	for _, n := range ns.nodesSlice {
+		if strings.EqualFold(n.FullName(), fullName) {
+			return n
+		}
+	}
+
+	return nil
+}
+
+// item returns a node of the slice item at index "i". The item node has the slice
+// field tags and the full name "<slice full name>.<i>".
+func (n *Node) item(i int) (*Node, error) {
+	if !n.IsSlice() {
+		return nil, fmt.Errorf("field '%v' is not a slice and cannot be indexed", n.FullName())
+	}
+	if i < 0 || i >= n.FieldValue.Len() {
+		return nil, fmt.Errorf("index %v is out of range for field '%v' with %v items", i, n.FullName(), n.FieldValue.Len())
+	}
+
+	v := n.FieldValue.Index(i)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, fmt.Errorf("field '%v' item %v is nil", n.FullName(), i)
+		}
+		v = v.Elem()
+	}
+
+	field := n.Field
+	field.Name = strconv.Itoa(i)
+	field.Type = v.Type()
+
+	tag := make(map[string]string, len(n.tag))
+	for k, tv := range n.tag {
+		tag[k] = tv
+	}
+
+	return &Node{
+		Prefix:     n.FullName(),
+		FieldValue: v,
+		Field:      field,
+		Index:      i,
+		tag:        tag,
+		meta:       make(map[string]string),
+	}, nil
+}
@@ -0,0 +1,89 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodes_FindPath(t *testing.T) {
+	type Server struct {
+		Host  string
+		Ports []int
+	}
+	c := &struct {
+		Name       string
+		Tags       []string
+		Servers    []Server
+		ServerPtrs []*Server
+	}{
+		Tags:       []string{"a", "b"},
+		Servers:    []Server{{Host: "h0"}, {Host: "h1", Ports: []int{80, 443}}},
+		ServerPtrs: []*Server{nil},
+	}
+	o := Options{NoFollow: []string{"time.Time"}}
+	nodes := MakeNodes(o, c)
+
+	// Plain field names.
+	n, err := nodes.FindPath("Name", false, o)
+	assert.NoError(t, err)
+	assert.Equal(t, "Name", n.FullName())
+	n, err = nodes.FindPath("name", true, o)
+	assert.NoError(t, err)
+	assert.Equal(t, "Name", n.FullName())
+	n, err = nodes.FindPath("name", false, o)
+	assert.NoError(t, err)
+	assert.Nil(t, n)
+
+	// Basic slice item.
+	n, err = nodes.FindPath("Tags.1", false, o)
+	assert.NoError(t, err)
+	assert.Equal(t, "Tags.1", n.FullName())
+	assert.NoError(t, n.SetFieldValue("c"))
+	assert.Equal(t, []string{"a", "c"}, c.Tags)
+
+	// Struct slice item fields (including nested slices).
+	n, err = nodes.FindPath("servers.0.host", true, o)
+	assert.NoError(t, err)
+	assert.Equal(t, "Servers.0.Host", n.FullName())
+	assert.NoError(t, n.SetFieldValue("x"))
+	assert.Equal(t, "x", c.Servers[0].Host)
+
+	n, err = nodes.FindPath("Servers.1.Ports.1", false, o)
+	assert.NoError(t, err)
+	assert.NoError(t, n.SetFieldValue("8443"))
+	assert.Equal(t, []int{80, 8443}, c.Servers[1].Ports)
+
+	n, err = nodes.FindPath("Servers.1", false, o)
+	assert.NoError(t, err)
+	assert.Equal(t, c.Servers[1], n.FieldValue.Interface())
+
+	n, err = nodes.FindPath("Servers.0.Missing", false, o)
+	assert.NoError(t, err)
+	assert.Nil(t, n)
+
+	// Errors.
+	_, err = nodes.FindPath("Servers.2.Host", false, o)
+	assert.EqualError(t, err, "index 2 is out of range for field 'Servers' with 2 items")
+	_, err = nodes.FindPath("Tags.-1", false, o)
+	assert.EqualError(t, err, "index -1 is out of range for field 'Tags' with 2 items")
+	_, err = nodes.FindPath("Name.0", false, o)
+	assert.EqualError(t, err, "field 'Name' is not a slice and cannot be indexed")
+	_, err = nodes.FindPath("Tags.0.Host", false, o)
+	assert.EqualError(t, err, "field 'Tags.0' is not a struct and has no field 'Host'")
+	_, err = nodes.FindPath("ServerPtrs.0.Host", false, o)
+	assert.EqualError(t, err, "field 'ServerPtrs' item 0 is nil")
+}
+
+func TestNodes_FindPath_Time(t *testing.T) {
+	type Window struct {
+		Start time.Time
+	}
+	c := &struct{ Windows []Window }{Windows: []Window{{}}}
+	o := Options{NoFollow: []string{"time.Time"}}
+
+	n, err := MakeNodes(o, c).FindPath("Windows.0.Start", false, o)
+	assert.NoError(t, err)
+	assert.True(t, n.IsTime())
+}
@@ -0,0 +1,222 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// restOpt is the 'config' tag option marking the map field that collects
+// config file keys not matched to a struct field.
+const restOpt = ",rest"
+
+// RestKeyFunc returns the config file key of the struct field "f". When
+// "inline" is true the fields of "f" (a struct) are read at the same level
+// as "f". Fields with an empty key that are not inlined are skipped.
+type RestKeyFunc func(f reflect.StructField) (key string, inline bool)
+
+// HasRest returns true if the struct (or struct pointer) "v" or any of its
+// nested structs has a 'config:",rest"' field.
+func HasRest(v interface{}) bool {
+	return hasRest(reflect.TypeOf(v), make(map[reflect.Type]bool))
+}
+
+func hasRest(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		if isRestField(t.Field(i)) || hasRest(t.Field(i).Type, seen) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetRest sets the 'config:",rest"' map[string]interface{} fields of the struct
+// pointer "v" to the "doc" keys not matched to a struct field at the same level. "doc"
+// is the generic map of the config file "v" was loaded from. Rest fields of nested
+// structs are set from the matching nested "doc" map.
+//
+// Keys are matched with "key" and are compared case-insensitively when "fold" is true.
+// Rest fields are not changed when there are no unmatched keys.
+func SetRest(v interface{}, doc map[string]interface{}, key RestKeyFunc, fold bool) error {
+	lvl, err := restLevelOf(reflect.ValueOf(v), key)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range lvl.children {
+		if sub, ok := lookupKey(doc, c.key, fold).(map[string]interface{}); ok {
+			if err := SetRest(c.v.Addr().Interface(), sub, key, fold); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !lvl.rest.IsValid() {
+		return nil
+	}
+
+	rest := make(map[string]interface{})
+	for k, val := range doc {
+		if !matchesAny(k, lvl.known, fold) {
+			rest[k] = val
+		}
+	}
+	if len(rest) > 0 {
+		lvl.rest.Set(reflect.ValueOf(rest))
+	}
+
+	return nil
+}
+
+// MergeRest adds the 'config:",rest"' field values of the struct pointer "v" to "doc",
+// the generic map of the marshaled "v". The rest field itself is removed from "doc"
+// and keys already in "doc" are not replaced. Rest fields of nested structs are added
+// to the matching nested "doc" map.
+//
+// Keys are matched with "key" and are compared case-insensitively when "fold" is true.
+func MergeRest(v interface{}, doc map[string]interface{}, key RestKeyFunc, fold bool) error {
+	lvl, err := restLevelOf(reflect.ValueOf(v), key)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range lvl.children {
+		if sub, ok := lookupKey(doc, c.key, fold).(map[string]interface{}); ok {
+			if err := MergeRest(c.v.Addr().Interface(), sub, key, fold); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !lvl.rest.IsValid() {
+		return nil
+	}
+
+	if lvl.restKey != "" {
+		for k := range doc {
+			if matchesAny(k, []string{lvl.restKey}, fold) {
+				delete(doc, k)
+			}
+		}
+	}
+
+	iter := lvl.rest.MapRange()
+	for iter.Next() {
+		if _, ok := doc[iter.Key().String()]; !ok {
+			doc[iter.Key().String()] = iter.Value().Interface()
+		}
+	}
+
+	return nil
+}
+
+// restLevel contains the struct fields read at one config file level.
+type restLevel struct {
+	known    []string      // Keys of the fields at the level.
+	rest     reflect.Value // Rest field (if any).
+	restKey  string        // Key of the rest field when marshaled.
+	children []restChild   // Struct fields that are a nested level.
+}
+
+type restChild struct {
+	key string
+	v   reflect.Value
+}
+
+// restLevelOf collects the fields of the struct (or struct pointer) "v" including
+// the fields of inlined structs.
+func restLevelOf(v reflect.Value, key RestKeyFunc) (*restLevel, error) {
+	lvl := &restLevel{}
+	return lvl, lvl.collect(v, key)
+}
+
+func (lvl *restLevel) collect(v reflect.Value, key RestKeyFunc) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous { // unexported
+			continue
+		}
+
+		if isRestField(sf) {
+			if sf.Type != reflect.TypeOf(map[string]interface{}{}) {
+				return fmt.Errorf("field '%v': 'rest' field must be a map[string]interface{}", sf.Name)
+			}
+			lvl.rest = v.Field(i)
+			lvl.restKey, _ = key(sf)
+			continue
+		}
+
+		k, inline := key(sf)
+		if inline {
+			if err := lvl.collect(v.Field(i), key); err != nil {
+				return err
+			}
+			continue
+		}
+		if k == "" {
+			continue
+		}
+		lvl.known = append(lvl.known, k)
+
+		fv := v.Field(i)
+		for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct && fv.CanAddr() {
+			lvl.children = append(lvl.children, restChild{key: k, v: fv})
+		}
+	}
+
+	return nil
+}
+
+// isRestField returns true when the struct field has the 'config:",rest"' tag.
+func isRestField(sf reflect.StructField) bool {
+	return strings.HasSuffix(sf.Tag.Get("config"), restOpt)
+}
+
+// lookupKey returns the "doc" value of "key". When "fold" is true an exact
+// match is preferred over a case-insensitive one.
+func lookupKey(doc map[string]interface{}, key string, fold bool) interface{} {
+	if val, ok := doc[key]; ok || !fold {
+		return val
+	}
+
+	for k, val := range doc {
+		if strings.EqualFold(k, key) {
+			return val
+		}
+	}
+
+	return nil
+}
+
+func matchesAny(k string, keys []string, fold bool) bool {
+	for _, key := range keys {
+		if k == key || (fold && strings.EqualFold(k, key)) {
+			return true
+		}
+	}
+
+	return false
+}
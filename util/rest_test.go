@@ -0,0 +1,73 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetRest(t *testing.T) {
+	type Base struct {
+		ID string
+	}
+	type DB struct {
+		Host string
+		Rest map[string]interface{} `config:",rest"`
+	}
+	type Config struct {
+		Base
+		Name string
+		DB   *DB
+		Rest map[string]interface{} `config:",rest"`
+	}
+
+	key := func(f reflect.StructField) (string, bool) {
+		if f.Anonymous {
+			return "", true
+		}
+		return f.Name, false
+	}
+
+	c := &Config{DB: &DB{}}
+	assert.True(t, HasRest(c))
+	assert.False(t, HasRest(&Base{}))
+
+	doc := map[string]interface{}{
+		"id":   "1",
+		"name": "a",
+		"new":  "b",
+		"db":   map[string]interface{}{"host": "localhost", "port": 5432},
+	}
+
+	// Case-insensitive keys.
+	assert.NoError(t, SetRest(c, doc, key, true))
+	assert.Equal(t, map[string]interface{}{"new": "b"}, c.Rest)
+	assert.Equal(t, map[string]interface{}{"port": 5432}, c.DB.Rest)
+
+	// Case-sensitive keys.
+	c = &Config{DB: &DB{}}
+	assert.NoError(t, SetRest(c, doc, key, false))
+	assert.Equal(t, doc, c.Rest)
+	assert.Nil(t, c.DB.Rest)
+
+	// Merge rest values back into the doc.
+	c = &Config{Name: "a", DB: &DB{Rest: map[string]interface{}{"port": 5432}}, Rest: map[string]interface{}{"new": 1, "Name": "x"}}
+	out := map[string]interface{}{
+		"Name": "a",
+		"Rest": map[string]interface{}{},
+		"DB":   map[string]interface{}{"Host": ""},
+	}
+	assert.NoError(t, MergeRest(c, out, key, false))
+	assert.Equal(t, map[string]interface{}{
+		"Name": "a",
+		"new":  1,
+		"DB":   map[string]interface{}{"Host": "", "port": 5432},
+	}, out)
+
+	// Invalid rest field type.
+	err := SetRest(&struct {
+		Rest map[string]string `config:",rest"`
+	}{}, doc, key, false)
+	assert.EqualError(t, err, "field 'Rest': 'rest' field must be a map[string]interface{}")
+}
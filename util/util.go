@@ -82,6 +82,17 @@ func ToLower(name string) string {
 	return strings.ToLower(name)
 }
 
+// ExpandHelp expands the supported help text placeholders:
+// - "{name}" is replaced with "name" (the generated field name)
+// - "{default}" is replaced with "defValue" (the field default value)
+func ExpandHelp(help, name, defValue string) string {
+	if !strings.Contains(help, "{") {
+		return help
+	}
+
+	return strings.NewReplacer("{name}", name, "{default}", defValue).Replace(help)
+}
+
 // IsStructPointer is a utility that checks if a given
 // interface is a struct pointer. If it is a struct pointer
 // then true is returned with no error message. Otherwise false
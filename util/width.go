@@ -0,0 +1,38 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// DefaultWidth is the default column width for wrapping help and show output.
+const DefaultWidth = 175
+
+// ParseWidth parses an output column width value. "width" can be:
+// - "" // the DefaultWidth.
+// - "0" // no wrapping.
+// - "auto" // the terminal width of stderr (DefaultWidth if stderr is not a terminal).
+// - a positive number of columns.
+func ParseWidth(width string) (int, error) {
+	switch strings.TrimSpace(width) {
+	case "":
+		return DefaultWidth, nil
+	case "auto":
+		w, _, err := term.GetSize(int(os.Stderr.Fd()))
+		if err != nil || w <= 0 {
+			return DefaultWidth, nil
+		}
+		return w, nil
+	}
+
+	w, err := strconv.Atoi(strings.TrimSpace(width))
+	if err != nil || w < 0 {
+		return 0, fmt.Errorf("invalid width '%v'", width)
+	}
+
+	return w, nil
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jbsmith7741/trial"
+)
+
+func TestParseWidth(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		return ParseWidth(args[0].(string))
+	}
+	cases := trial.Cases{
+		"default": {
+			Input:    "",
+			Expected: DefaultWidth,
+		},
+		"no wrap": {
+			Input:    "0",
+			Expected: 0,
+		},
+		"columns": {
+			Input:    "80",
+			Expected: 80,
+		},
+		"auto without terminal": {
+			Input:    "auto",
+			Expected: DefaultWidth,
+		},
+		"negative": {
+			Input:       "-1",
+			ExpectedErr: errors.New("invalid width '-1'"),
+		},
+		"invalid": {
+			Input:       "wide",
+			ExpectedErr: errors.New("invalid width 'wide'"),
+		},
+	}
+	trial.New(fn, cases).Test(t)
+}